@@ -0,0 +1,121 @@
+package client
+
+import (
+	"context"
+	"dniprom-cli/internal/container"
+	"dniprom-cli/internal/model/network"
+	"dniprom-cli/internal/service/cache"
+	"dniprom-cli/pkg/logger"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// cachePayloadVersion is part of every cache key. Bump it whenever the cached
+// network models change, so that entries written by an older build are not
+// decoded into the new structs.
+const cachePayloadVersion = 2
+
+type cachedDniproClient struct {
+	container container.Container
+	client    DniproClient
+	cache     cache.Cache
+}
+
+// NewCachedDniproClient wraps client with an on-disk response cache. The cache
+// is consulted on every call, so disabling it in the config at runtime (e.g.
+// via --no-cache) takes effect immediately.
+func NewCachedDniproClient(container container.Container, client DniproClient, cache cache.Cache) DniproClient {
+	return &cachedDniproClient{
+		container: container,
+		client:    client,
+		cache:     cache,
+	}
+}
+
+func (c *cachedDniproClient) FetchAutocompleteProduct(ctx context.Context, code string) (*network.Product, error) {
+	products, err := c.SearchProducts(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	return findProduct(c.container.GetLogger(), products, code)
+}
+
+func (c *cachedDniproClient) SearchProducts(ctx context.Context, query string) ([]network.Product, error) {
+	key := c.key(SearchAPIEndpoint, url.Values{"q": {query}})
+	var searchResponse network.SearchResponse
+	// Entries are validated again, as the required fields may have changed
+	// since they were stored.
+	if c.get(SearchAPIEndpoint, key, &searchResponse) && searchResponse.Validate() == nil {
+		return searchResponse.Products, nil
+	}
+	products, err := c.client.SearchProducts(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	searchResponse = network.SearchResponse{Products: products}
+	c.put(SearchAPIEndpoint, key, c.container.GetConfig().Cache.SearchTTL, searchResponse)
+	return products, nil
+}
+
+func (c *cachedDniproClient) GetWarranty(ctx context.Context, id int64) ([]network.WarrantyEntry, error) {
+	key := c.key(WarrantyAPIEndpoint, url.Values{"productId": {fmt.Sprintf("%d", id)}})
+	var warranty []network.WarrantyEntry
+	if c.get(WarrantyAPIEndpoint, key, &warranty) {
+		return warranty, nil
+	}
+	warranty, err := c.client.GetWarranty(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	c.put(WarrantyAPIEndpoint, key, c.container.GetConfig().Cache.WarrantyTTL, warranty)
+	return warranty, nil
+}
+
+func (c *cachedDniproClient) FetchSitemap(ctx context.Context, sitemapURL string) (*network.Sitemap, error) {
+	return c.client.FetchSitemap(ctx, sitemapURL)
+}
+
+func (c *cachedDniproClient) FetchProductPage(ctx context.Context, pageURL string) (*network.ProductPage, error) {
+	return c.client.FetchProductPage(ctx, pageURL)
+}
+
+// key identifies a request in the cache. It carries the base URL, so that
+// responses of different hosts (e.g. the fake server) never mix, and the
+// payload version.
+func (c *cachedDniproClient) key(endpoint string, query url.Values) string {
+	baseURL := strings.TrimRight(c.container.GetConfig().BaseURL, "/")
+	return fmt.Sprintf("v%d %s/%s?%s", cachePayloadVersion, baseURL, endpoint, query.Encode())
+}
+
+func (c *cachedDniproClient) get(endpoint string, key string, value any) bool {
+	if !c.container.GetConfig().Cache.Enabled {
+		return false
+	}
+	found, err := c.cache.Get(endpoint, key, value)
+	if err != nil {
+		return false
+	}
+	if found {
+		c.container.GetLogger().Debug(
+			"cache hit",
+			logger.F("endpoint", endpoint),
+			logger.F("key", key),
+		)
+	}
+	return found
+}
+
+func (c *cachedDniproClient) put(endpoint string, key string, ttl time.Duration, value any) {
+	if !c.container.GetConfig().Cache.Enabled {
+		return
+	}
+	if err := c.cache.Put(endpoint, key, ttl, value); err != nil {
+		c.container.GetLogger().Warn(
+			"fail to store response in cache",
+			logger.F("endpoint", endpoint),
+			logger.FError(err),
+		)
+	}
+}
@@ -1,13 +1,19 @@
 package client
 
 import (
+	"compress/gzip"
+	"context"
 	"dniprom-cli/internal/container"
 	"dniprom-cli/internal/model/network"
 	"dniprom-cli/pkg/logger"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
+	"golang.org/x/time/rate"
+	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 )
 
@@ -17,25 +23,47 @@ const (
 )
 
 type DniproClient interface {
-	FetchAutocompleteProduct(code string) (*network.Product, error)
-	GetWarranty(id int64) (string, error)
+	FetchAutocompleteProduct(ctx context.Context, code string) (*network.Product, error)
+	SearchProducts(ctx context.Context, query string) ([]network.Product, error)
+	GetWarranty(ctx context.Context, id int64) ([]network.WarrantyEntry, error)
+	FetchSitemap(ctx context.Context, sitemapURL string) (*network.Sitemap, error)
+	FetchProductPage(ctx context.Context, pageURL string) (*network.ProductPage, error)
 }
 
 type dniproClient struct {
 	container container.Container
 	client    *http.Client
+	limiter   *rate.Limiter
+	retry     retryPolicy
 }
 
-func NewDniproClient(container container.Container) DniproClient {
+func NewDniproClient(container container.Container) (DniproClient, error) {
+	config := container.GetConfig()
+	transport, err := newTransport(config.HTTP)
+	if err != nil {
+		container.GetLogger().Error("fail to configure http transport", logger.FError(err))
+		return nil, err
+	}
 	return &dniproClient{
 		container: container,
 		client: &http.Client{
-			Timeout: 10 * time.Second,
+			Timeout:   config.HTTP.Timeout,
+			Transport: newFixtureTransport(container, transport),
 		},
+		limiter: rate.NewLimiter(rate.Limit(config.Concurrency.RequestsPerSecond), config.Concurrency.Burst),
+		retry:   newRetryPolicy(config.Retry),
+	}, nil
+}
+
+func (d *dniproClient) FetchAutocompleteProduct(ctx context.Context, code string) (*network.Product, error) {
+	products, err := d.SearchProducts(ctx, code)
+	if err != nil {
+		return nil, err
 	}
+	return findProduct(d.container.GetLogger(), products, code)
 }
 
-func (d *dniproClient) FetchAutocompleteProduct(code string) (*network.Product, error) {
+func (d *dniproClient) SearchProducts(ctx context.Context, query string) ([]network.Product, error) {
 	log := d.container.GetLogger()
 	fullPath := d.GetPath(SearchAPIEndpoint)
 
@@ -45,20 +73,20 @@ func (d *dniproClient) FetchAutocompleteProduct(code string) (*network.Product,
 		return nil, err
 	}
 	q := u.Query()
-	q.Set("q", code)
+	q.Set("q", query)
 	u.RawQuery = q.Encode()
 
 	log.Debug(
 		"request path",
 		logger.F("path", u.String()),
 	)
-	req, err := d.buildRequest(u)
+	req, err := d.buildRequest(ctx, u)
 	if err != nil {
 		log.Error("fail to build request", logger.FError(err))
 		return nil, err
 	}
 
-	resp, err := d.client.Do(req)
+	resp, err := d.do(req, isJSONContentType)
 	if err != nil {
 		log.Error("fail to make request", logger.FError(err))
 		return nil, err
@@ -68,44 +96,30 @@ func (d *dniproClient) FetchAutocompleteProduct(code string) (*network.Product,
 		_ = resp.Body.Close()
 	}()
 
-	var data map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+	var searchResponse network.SearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&searchResponse); err != nil {
 		log.Error("fail to decode response", logger.FError(err))
 		return nil, err
 	}
-
-	products, ok := data["products"].([]interface{})
-	if !ok {
-		log.Error("products are missing", logger.F("code", code))
-		return nil, nil
-	}
-
-	if len(products) < 1 {
-		log.Error("products not found", logger.F("code", code))
-		return nil, nil
-	}
-
-	productJSON, err := json.Marshal(products[0])
-	if err != nil {
-		log.Error("fail to marshal product", logger.FError(err))
-		return nil, nil
-	}
-	var autocompleteProduct network.Product
-	if err := json.Unmarshal(productJSON, &autocompleteProduct); err != nil {
-		log.Error("fail to unmarshal product", logger.FError(err))
+	if err := searchResponse.Validate(); err != nil {
+		log.Error(
+			"unexpected search response",
+			logger.F("query", query),
+			logger.FError(err),
+		)
 		return nil, err
 	}
-	return &autocompleteProduct, nil
+	return searchResponse.Products, nil
 }
 
-func (d *dniproClient) GetWarranty(id int64) (string, error) {
+func (d *dniproClient) GetWarranty(ctx context.Context, id int64) ([]network.WarrantyEntry, error) {
 	log := d.container.GetLogger()
 	fullPath := d.GetPath(WarrantyAPIEndpoint)
 
 	u, err := url.Parse(fullPath)
 	if err != nil {
 		log.Error("error parsing url", logger.FError(err))
-		return "", err
+		return nil, err
 	}
 	q := u.Query()
 	q.Set("productId", fmt.Sprintf("%d", id))
@@ -115,47 +129,155 @@ func (d *dniproClient) GetWarranty(id int64) (string, error) {
 		"request path",
 		logger.F("path", u.String()),
 	)
-	req, err := d.buildRequest(u)
+	req, err := d.buildRequest(ctx, u)
 	if err != nil {
 		log.Error("fail to build request", logger.FError(err))
-		return "", err
+		return nil, err
 	}
 
-	resp, err := d.client.Do(req)
+	resp, err := d.do(req, isJSONContentType)
 	if err != nil {
 		log.Error("fail to make request", logger.FError(err))
-		return "", err
+		return nil, err
 	}
 	defer func() {
 		_ = resp.Body.Close()
 	}()
 
-	var data map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+	var maintenanceResponse network.ServiceMaintenanceResponse
+	if err := json.NewDecoder(resp.Body).Decode(&maintenanceResponse); err != nil {
 		log.Error("fail to decode response", logger.FError(err))
-		return "", err
+		return nil, err
+	}
+	if err := maintenanceResponse.Validate(); err != nil {
+		log.Error(
+			"unexpected warranty response",
+			logger.F("productId", id),
+			logger.FError(err),
+		)
+		return nil, err
+	}
+	if len(maintenanceResponse.Warranty) < 1 {
+		log.Error("warranty not found", logger.F("productId", id))
+	}
+	return maintenanceResponse.Warranty, nil
+}
+
+func (d *dniproClient) FetchSitemap(ctx context.Context, sitemapURL string) (*network.Sitemap, error) {
+	log := d.container.GetLogger()
+	u, err := url.Parse(d.GetPath(""))
+	if err != nil {
+		log.Error("error parsing url", logger.FError(err))
+		return nil, err
+	}
+	// Child sitemaps are absolute URLs, the configured one may be relative
+	// to base_url.
+	u, err = u.Parse(sitemapURL)
+	if err != nil {
+		log.Error("error parsing sitemap url", logger.FError(err))
+		return nil, err
+	}
+
+	log.Debug(
+		"request path",
+		logger.F("path", u.String()),
+	)
+	req, err := d.buildRequest(ctx, u)
+	if err != nil {
+		log.Error("fail to build request", logger.FError(err))
+		return nil, err
+	}
+
+	resp, err := d.do(req, isXMLContentType)
+	if err != nil {
+		log.Error("fail to make request", logger.FError(err))
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	var body io.Reader = resp.Body
+	gzipped := strings.HasSuffix(u.Path, ".gz") || strings.Contains(resp.Header.Get("Content-Type"), "gzip")
+	if gzipped && !resp.Uncompressed {
+		gzipReader, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			log.Error("fail to open gzipped sitemap", logger.FError(err))
+			return nil, err
+		}
+		defer func() {
+			_ = gzipReader.Close()
+		}()
+		body = gzipReader
+	}
+
+	var sitemap network.Sitemap
+	if err := xml.NewDecoder(body).Decode(&sitemap); err != nil {
+		log.Error("fail to decode sitemap", logger.FError(err))
+		return nil, err
+	}
+	if err := sitemap.Validate(); err != nil {
+		log.Error(
+			"unexpected sitemap",
+			logger.F("path", u.String()),
+			logger.FError(err),
+		)
+		return nil, err
 	}
-	warrantyRaw, ok := data["warranty"].([]interface{})
-	if !ok {
-		log.Error("warranty is missing")
-		return "", nil
+	return &sitemap, nil
+}
+
+func (d *dniproClient) FetchProductPage(ctx context.Context, pageURL string) (*network.ProductPage, error) {
+	log := d.container.GetLogger()
+	u, err := url.Parse(d.GetPath(""))
+	if err != nil {
+		log.Error("error parsing url", logger.FError(err))
+		return nil, err
 	}
-	if len(warrantyRaw) < 1 {
-		log.Error("warranty not found")
-		return "", nil
+	u, err = u.Parse(pageURL)
+	if err != nil {
+		log.Error("error parsing product page url", logger.FError(err))
+		return nil, err
 	}
 
-	warrantyRawItem, ok := warrantyRaw[0].(map[string]interface{})
-	if !ok {
-		log.Error("warranty item is missing")
-		return "", nil
+	log.Debug(
+		"request path",
+		logger.F("path", u.String()),
+	)
+	req, err := d.buildRequest(ctx, u)
+	if err != nil {
+		log.Error("fail to build request", logger.FError(err))
+		return nil, err
 	}
-	text, ok := warrantyRawItem["warranty"].(string)
-	if !ok {
-		log.Error("warranty text is missing")
-		return "", nil
+	// The product page is a regular HTML document, not an API call.
+	req.Header.Del("Content-Type")
+	req.Header.Del("X-Requested-With")
+	req.Header.Set("Accept", "text/html")
+
+	resp, err := d.do(req, isHTMLContentType)
+	if err != nil {
+		log.Error("fail to make request", logger.FError(err))
+		return nil, err
 	}
-	return text, nil
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		log.Error("fail to read product page", logger.FError(err))
+		return nil, err
+	}
+	page := network.ParseProductPage(body)
+	if err := page.Validate(); err != nil {
+		log.Error(
+			"unexpected product page",
+			logger.F("path", u.String()),
+			logger.FError(err),
+		)
+		return nil, err
+	}
+	return page, nil
 }
 
 func (d *dniproClient) GetPath(endpoint string) string {
@@ -166,17 +288,110 @@ func (d *dniproClient) GetPath(endpoint string) string {
 	)
 }
 
-func (d *dniproClient) buildRequest(u *url.URL) (*http.Request, error) {
+func (d *dniproClient) do(req *http.Request, acceptContentType func(string) bool) (*http.Response, error) {
+	log := d.container.GetLogger()
+	ctx := req.Context()
+
+	replaying := d.container.GetConfig().Fixtures.ReplayDir != ""
+
+	for attempt := 1; ; attempt++ {
+		if !replaying {
+			if err := d.limiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+		resp, err := d.client.Do(req)
+		if ctx.Err() != nil || attempt >= d.retry.maxAttempts || !d.retry.shouldRetry(resp, err) {
+			if err != nil {
+				return nil, err
+			}
+			if err := checkResponse(resp, acceptContentType); err != nil {
+				return nil, err
+			}
+			return resp, nil
+		}
+
+		delay := d.retry.delay(attempt, resp)
+		fields := []logger.Field{
+			logger.F("path", req.URL.String()),
+			logger.F("attempt", attempt),
+			logger.F("maxAttempts", d.retry.maxAttempts),
+			logger.F("delay", delay),
+		}
+		if err != nil {
+			fields = append(fields, logger.FError(err))
+		} else {
+			fields = append(fields, logger.F("status", resp.StatusCode))
+			_ = resp.Body.Close()
+		}
+		log.Warn("request failed, retrying", fields...)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (d *dniproClient) buildRequest(ctx context.Context, u *url.URL) (*http.Request, error) {
 	log := d.container.GetLogger()
 
-	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
 	if err != nil {
 		log.Error("fail to create request", logger.FError(err))
 		return nil, err
 	}
-	req.Header.Set("User-Agent", "GoClient/1.0")
+	httpConfig := d.container.GetConfig().HTTP
+	req.Header.Set("User-Agent", httpConfig.UserAgent)
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("X-Requested-With", "XMLHttpRequest")
+	for key, value := range httpConfig.Headers {
+		if value == "" {
+			req.Header.Del(key)
+			continue
+		}
+		req.Header.Set(key, value)
+	}
 
 	return req, nil
 }
+
+// findProduct picks the search result whose vendor code is exactly code.
+func findProduct(log logger.Logger, products []network.Product, code string) (*network.Product, error) {
+	if len(products) < 1 {
+		log.Error("products not found", logger.F("code", code))
+		return nil, ErrProductNotFound
+	}
+	product, err := matchProductCode(products, code)
+	if err != nil {
+		log.Error(
+			"fail to match product code",
+			logger.F("code", code),
+			logger.F("candidates", len(products)),
+			logger.FError(err),
+		)
+		return nil, err
+	}
+	return product, nil
+}
+
+func matchProductCode(products []network.Product, code string) (*network.Product, error) {
+	code = strings.TrimSpace(code)
+	var matched *network.Product
+	for i := range products {
+		if strings.TrimSpace(products[i].VendorCode.String()) != code {
+			continue
+		}
+		if matched != nil {
+			return nil, ErrAmbiguousMatch
+		}
+		matched = &products[i]
+	}
+	if matched == nil {
+		return nil, ErrNoExactMatch
+	}
+	return matched, nil
+}
@@ -0,0 +1,46 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrProductNotFound = errors.New("product not found")
+	ErrNoExactMatch    = errors.New("no product matches the code exactly")
+	ErrAmbiguousMatch  = errors.New("several products match the code exactly")
+)
+
+var (
+	ErrNotFound         = errors.New("resource not found")
+	ErrRateLimited      = errors.New("rate limited")
+	ErrUpstream         = errors.New("upstream error")
+	ErrUnexpectedStatus = errors.New("unexpected status")
+	ErrBadContentType   = errors.New("unexpected content type")
+)
+
+// ResponseError describes an HTTP response that could not be used as a
+// Dnipro-M API payload. Kind is one of the sentinel errors above and can be
+// matched with errors.Is.
+type ResponseError struct {
+	Kind        error
+	StatusCode  int
+	URL         string
+	ContentType string
+	Body        string
+}
+
+func (e *ResponseError) Error() string {
+	return fmt.Sprintf(
+		"%s: status %d, content type %q, url %s, body %q",
+		e.Kind.Error(),
+		e.StatusCode,
+		e.ContentType,
+		e.URL,
+		e.Body,
+	)
+}
+
+func (e *ResponseError) Unwrap() error {
+	return e.Kind
+}
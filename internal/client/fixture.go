@@ -0,0 +1,161 @@
+package client
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"dniprom-cli/internal/container"
+	"dniprom-cli/pkg/fsx"
+	"dniprom-cli/pkg/logger"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const maxFixtureNameLength = 96
+
+var ErrFixtureNotFound = errors.New("fixture not found")
+
+type Fixture struct {
+	Request  FixtureRequest  `json:"request"`
+	Response FixtureResponse `json:"response"`
+}
+
+type FixtureRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+}
+
+type FixtureResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       string      `json:"body"`
+}
+
+// fixtureTransport records every exchange into the configured record
+// directory, or serves responses from the replay directory without touching
+// the network. The configuration is read per request so command line flags
+// applied after construction are honored.
+type fixtureTransport struct {
+	container container.Container
+	base      http.RoundTripper
+}
+
+func newFixtureTransport(container container.Container, base http.RoundTripper) http.RoundTripper {
+	return &fixtureTransport{
+		container: container,
+		base:      base,
+	}
+}
+
+func (t *fixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	fixtures := t.container.GetConfig().Fixtures
+	if fixtures.ReplayDir != "" {
+		return ReplayFixture(fixtures.ReplayDir, req)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || fixtures.RecordDir == "" {
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err := RecordFixture(fixtures.RecordDir, req, resp.StatusCode, resp.Header, body); err != nil {
+		t.container.GetLogger().Warn(
+			"fail to record fixture",
+			logger.F("path", req.URL.String()),
+			logger.FError(err),
+		)
+	}
+	return resp, nil
+}
+
+// RecordFixture stores a single exchange under dir. The fixture is keyed by
+// path and query only, so it can be replayed against any base URL.
+func RecordFixture(dir string, req *http.Request, statusCode int, header http.Header, body []byte) error {
+	data, err := json.MarshalIndent(Fixture{
+		Request: FixtureRequest{
+			Method: req.Method,
+			URL:    req.URL.String(),
+		},
+		Response: FixtureResponse{
+			StatusCode: statusCode,
+			Header:     header,
+			Body:       string(body),
+		},
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := FixturePath(dir, req)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return fsx.WriteFileAtomic(path, data, 0o644)
+}
+
+// ReplayFixture builds the response recorded for req under dir.
+func ReplayFixture(dir string, req *http.Request) (*http.Response, error) {
+	path := FixturePath(dir, req)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s %s (%s)", ErrFixtureNotFound, req.Method, req.URL.String(), path)
+	} else if err != nil {
+		return nil, err
+	}
+
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("decode fixture %s: %w", path, err)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", fixture.Response.StatusCode, http.StatusText(fixture.Response.StatusCode)),
+		StatusCode:    fixture.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        fixture.Response.Header,
+		Body:          io.NopCloser(strings.NewReader(fixture.Response.Body)),
+		ContentLength: int64(len(fixture.Response.Body)),
+		Request:       req,
+	}, nil
+}
+
+func FixturePath(dir string, req *http.Request) string {
+	endpoint := fixtureName(strings.Trim(req.URL.Path, "/"))
+	query := fixtureName(req.URL.Query().Encode())
+	name := strings.ToLower(req.Method)
+	if query != "" {
+		name += "_" + query
+	}
+	if len(name) > maxFixtureNameLength {
+		sum := sha256.Sum256([]byte(name))
+		name = name[:maxFixtureNameLength-17] + "_" + hex.EncodeToString(sum[:8])
+	}
+	return filepath.Join(dir, endpoint, name+".json")
+}
+
+func fixtureName(value string) string {
+	name := make([]rune, 0, len(value))
+	for _, r := range value {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+			name = append(name, r)
+		default:
+			name = append(name, '_')
+		}
+	}
+	return strings.Trim(string(name), "_")
+}
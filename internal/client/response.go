@@ -0,0 +1,87 @@
+package client
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+const bodySnippetLimit = 512
+
+// checkResponse classifies resp and returns a *ResponseError when it is not
+// successful or its content type is not accepted. The body is consumed and
+// closed in that case.
+func checkResponse(resp *http.Response, acceptContentType func(string) bool) error {
+	kind := classifyStatus(resp.StatusCode)
+	contentType := resp.Header.Get("Content-Type")
+	if kind == nil && !acceptContentType(contentType) {
+		kind = ErrBadContentType
+	}
+	if kind == nil {
+		return nil
+	}
+
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, bodySnippetLimit))
+	_ = resp.Body.Close()
+	return &ResponseError{
+		Kind:        kind,
+		StatusCode:  resp.StatusCode,
+		URL:         resp.Request.URL.String(),
+		ContentType: contentType,
+		Body:        strings.TrimSpace(string(snippet)),
+	}
+}
+
+func classifyStatus(statusCode int) error {
+	switch {
+	case statusCode >= 200 && statusCode < 300:
+		return nil
+	case statusCode == http.StatusNotFound:
+		return ErrNotFound
+	case statusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case statusCode >= http.StatusInternalServerError:
+		return ErrUpstream
+	}
+	return ErrUnexpectedStatus
+}
+
+func isJSONContentType(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" ||
+		mediaType == "text/json" ||
+		strings.HasSuffix(mediaType, "+json")
+}
+
+func isXMLContentType(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/xml" ||
+		mediaType == "text/xml" ||
+		mediaType == "application/gzip" ||
+		mediaType == "application/x-gzip" ||
+		strings.HasSuffix(mediaType, "+xml")
+}
+
+func isHTMLContentType(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "text/html" || mediaType == "application/xhtml+xml"
+}
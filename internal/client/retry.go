@@ -0,0 +1,79 @@
+package client
+
+import (
+	"dniprom-cli/internal/model"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type retryPolicy struct {
+	maxAttempts int
+	baseBackoff time.Duration
+	maxBackoff  time.Duration
+	jitter      float64
+}
+
+func newRetryPolicy(config model.RetryConfig) retryPolicy {
+	return retryPolicy{
+		maxAttempts: config.MaxAttempts,
+		baseBackoff: config.BaseBackoff,
+		maxBackoff:  config.MaxBackoff,
+		jitter:      config.Jitter,
+	}
+}
+
+// shouldRetry reports whether a request that ended with resp or err deserves
+// another attempt.
+func (p retryPolicy) shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, ErrFixtureNotFound)
+	}
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return true
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return true
+	}
+	return false
+}
+
+// delay returns how long to wait before the next attempt. attempt starts at 1
+// for the first failed attempt. The delay never exceeds maxBackoff, even when
+// the server asks for a longer Retry-After.
+func (p retryPolicy) delay(attempt int, resp *http.Response) time.Duration {
+	if retryAfter, ok := parseRetryAfter(resp); ok {
+		return min(retryAfter, p.maxBackoff)
+	}
+	backoff := float64(p.baseBackoff) * math.Pow(2, float64(attempt-1))
+	if backoff > float64(p.maxBackoff) {
+		backoff = float64(p.maxBackoff)
+	}
+	if p.jitter > 0 {
+		backoff += backoff * p.jitter * (2*rand.Float64() - 1)
+	}
+	return min(time.Duration(backoff), p.maxBackoff)
+}
+
+func parseRetryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func newRetryResponse(status int, retryAfter string) *http.Response {
+	resp := &http.Response{StatusCode: status, Header: http.Header{}}
+	if retryAfter != "" {
+		resp.Header.Set("Retry-After", retryAfter)
+	}
+	return resp
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		resp   *http.Response
+		want   time.Duration
+		wantOK bool
+	}{
+		{name: "no response"},
+		{name: "seconds", resp: newRetryResponse(http.StatusTooManyRequests, "3"), want: 3 * time.Second, wantOK: true},
+		{name: "unavailable", resp: newRetryResponse(http.StatusServiceUnavailable, "0"), wantOK: true},
+		{name: "past date", resp: newRetryResponse(http.StatusTooManyRequests, "Wed, 21 Oct 2015 07:28:00 GMT"), wantOK: true},
+		{name: "negative", resp: newRetryResponse(http.StatusTooManyRequests, "-1")},
+		{name: "garbage", resp: newRetryResponse(http.StatusTooManyRequests, "soon")},
+		{name: "missing", resp: newRetryResponse(http.StatusTooManyRequests, "")},
+		{name: "other status", resp: newRetryResponse(http.StatusInternalServerError, "3")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseRetryAfter(tt.resp)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("parseRetryAfter() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := retryPolicy{
+		maxAttempts: 5,
+		baseBackoff: time.Second,
+		maxBackoff:  10 * time.Second,
+	}
+	tests := []struct {
+		name    string
+		attempt int
+		resp    *http.Response
+		want    time.Duration
+	}{
+		{name: "first attempt", attempt: 1, want: time.Second},
+		{name: "exponential", attempt: 3, want: 4 * time.Second},
+		{name: "capped", attempt: 10, want: 10 * time.Second},
+		{name: "retry after", attempt: 1, resp: newRetryResponse(http.StatusTooManyRequests, "5"), want: 5 * time.Second},
+		{name: "retry after capped", attempt: 1, resp: newRetryResponse(http.StatusTooManyRequests, "3600"), want: 10 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.delay(tt.attempt, tt.resp); got != tt.want {
+				t.Errorf("delay(%d) = %v, want %v", tt.attempt, got, tt.want)
+			}
+		})
+	}
+}
+
+// Positive jitter must not push the backoff above maxBackoff.
+func TestRetryPolicyDelayJitter(t *testing.T) {
+	policy := retryPolicy{
+		maxAttempts: 5,
+		baseBackoff: time.Second,
+		maxBackoff:  4 * time.Second,
+		jitter:      0.5,
+	}
+	for range 100 {
+		got := policy.delay(3, nil)
+		if got < 2*time.Second || got > 4*time.Second {
+			t.Fatalf("delay(3) = %v, want between 2s and 4s", got)
+		}
+	}
+}
@@ -6,11 +6,13 @@ import (
 	"dniprom-cli/internal/command"
 	"dniprom-cli/internal/container"
 	"dniprom-cli/internal/model"
-	"dniprom-cli/internal/service/recorder"
+	"dniprom-cli/internal/service/cache"
 	"dniprom-cli/pkg/logger"
 	"fmt"
 	"github.com/spf13/cobra"
 	"os"
+	"os/signal"
+	"syscall"
 )
 
 func main() {
@@ -19,38 +21,159 @@ func main() {
 		fmt.Println("failed to load config", err.Error())
 		os.Exit(1)
 	}
-	ctx := context.Background()
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	// Restore the default signal handling once the run is cancelled, so that a
+	// second signal kills the process while the footer is still being written.
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
 	log := logger.NewLogger(conf.GetLoggerENV())
 	cont := container.NewContainer(log, conf)
 
-	dniproClient := client.NewDniproClient(cont)
-	recorder, err := recorder.NewRecorder(ctx, cont)
+	responseCache := cache.NewCache(cont, client.SearchAPIEndpoint, client.WarrantyAPIEndpoint)
+	baseDniproClient, err := client.NewDniproClient(cont)
 	if err != nil {
-		log.Fatal("fail to create recorder", logger.FError(err))
+		log.Fatal("fail to create dnipro client", logger.FError(err))
 		return
 	}
+	dniproClient := client.NewCachedDniproClient(
+		cont,
+		baseDniproClient,
+		responseCache,
+	)
 
 	warrantyCommand := command.NewWarrantyCommand(
 		cont,
 		dniproClient,
-		recorder,
 	)
+	cacheCommand := command.NewCacheCommand(cont, responseCache)
+	devCommand := command.NewDevCommand(cont)
+	discoverCommand := command.NewDiscoverCommand(cont, dniproClient)
+	searchCommand := command.NewSearchCommand(cont, dniproClient)
+	lookupCommand := command.NewLookupCommand(cont, dniproClient)
+	historyCommand := command.NewHistoryCommand(cont)
 
 	rootCmd := &cobra.Command{
-		Use:   "root",
-		Short: "DniproM CLI tool",
+		Use:     "root",
+		Short:   "DniproM CLI tool",
+		Version: model.ToolVersion(),
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			if noCache, _ := cmd.Flags().GetBool("no-cache"); noCache {
+				conf.Cache.Enabled = false
+			}
+			if recordDir, _ := cmd.Flags().GetString("record"); recordDir != "" {
+				conf.Fixtures.RecordDir = recordDir
+			}
+			if replayDir, _ := cmd.Flags().GetString("replay"); replayDir != "" {
+				conf.Fixtures.ReplayDir = replayDir
+			}
+			if conf.Fixtures.RecordDir != "" || conf.Fixtures.ReplayDir != "" {
+				// Cached responses would bypass the recorded traffic.
+				conf.Cache.Enabled = false
+			}
+		},
 	}
+	rootCmd.PersistentFlags().Duration("timeout", 0, "abort the whole run after this duration, e.g. 30m (0 disables)")
+	rootCmd.PersistentFlags().Bool("no-cache", false, "bypass the on-disk response cache")
+	rootCmd.PersistentFlags().String("record", "", "save every Dnipro-M request/response as fixture files in this directory")
+	rootCmd.PersistentFlags().String("replay", "", "serve Dnipro-M responses from fixture files in this directory instead of the network")
 	warrantyCmd := &cobra.Command{
 		Use:   "warranty",
 		Short: "Collect warranty information",
 		Long:  "Collect warranty information for products.",
 		Run:   warrantyCommand.Run,
 	}
+	warrantyCmd.Flags().StringSlice(
+		"enrich",
+		nil,
+		"optional product details to collect: category, brand, availability, url, image, rating",
+	)
+	warrantyCmd.Flags().StringP("output", "o", "sheets", "output: sheets, csv, xlsx, json or ndjson")
+	warrantyCmd.Flags().String("out-file", "", "file written by file outputs, json and ndjson default to stdout")
+
+	cacheCmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the response cache",
+	}
+	cacheClearCmd := &cobra.Command{
+		Use:   "clear",
+		Short: "Remove every cached response",
+		Run:   cacheCommand.Clear,
+	}
+	cacheStatsCmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show cached responses per endpoint",
+		Run:   cacheCommand.Stats,
+	}
+	cacheCmd.AddCommand(cacheClearCmd, cacheStatsCmd)
+
+	devCmd := &cobra.Command{
+		Use:   "dev",
+		Short: "Developer tools",
+	}
+	fakeServerCmd := &cobra.Command{
+		Use:   "fake-server",
+		Short: "Run a fake Dnipro-M server",
+		Long:  "Run a local server emulating the Dnipro-M search and warranty endpoints from a fixture catalog.",
+		Run:   devCommand.FakeServer,
+	}
+	fakeServerCmd.Flags().String("catalog", "./dev/fake_catalog.yml", "path to the YAML/JSON fixture catalog")
+	fakeServerCmd.Flags().String("addr", "127.0.0.1:8080", "address to listen on")
+	devCmd.AddCommand(fakeServerCmd)
+
+	discoverCmd := &cobra.Command{
+		Use:   "discover",
+		Short: "Discover product codes",
+		Long:  "Walk the Dnipro-M sitemap, optionally filter the products by category, write the product codes found into a codes file and list the newly listed and delisted ones.",
+		Args:  cobra.NoArgs,
+		Run:   discoverCommand.Run,
+	}
+	discoverCmd.Flags().String("out", "product_codes.txt", "write the codes into this file, one per line (empty to skip)")
+	discoverCmd.Flags().Bool("write-config", false, "also replace product_codes in config.yml with the discovered codes")
+	discoverCmd.Flags().String("sitemap-url", "", "sitemap index to walk (defaults to discover.sitemap_url)")
+	discoverCmd.Flags().StringSlice("include", nil, "keep only products from categories matching these terms (defaults to discover.include)")
+	discoverCmd.Flags().StringSlice("exclude", nil, "drop products from categories matching these terms (defaults to discover.exclude)")
+
+	searchCmd := &cobra.Command{
+		Use:   "search <query>",
+		Short: "Search products",
+		Long:  "Search Dnipro-M products by free text and print every candidate, e.g. to find codes to track.",
+		Args:  cobra.MinimumNArgs(1),
+		Run:   searchCommand.Run,
+	}
+	searchCmd.Flags().StringP("output", "o", "table", "output format: table or json")
+
+	lookupCmd := &cobra.Command{
+		Use:   "lookup <code> [<code>...]",
+		Short: "Look up products by code",
+		Long:  "Fetch warranty, prices and details for the given product codes and print them without touching Google Sheets.",
+		Args:  cobra.MinimumNArgs(1),
+		Run:   lookupCommand.Run,
+	}
+	lookupCmd.Flags().StringP("output", "o", "table", "output format: table, json or yaml")
+	lookupCmd.Flags().StringSlice(
+		"enrich",
+		nil,
+		"optional product details to collect: category, brand, availability, url, image, rating",
+	)
+
+	historyCmd := &cobra.Command{
+		Use:   "history [<code>]",
+		Short: "Show price and warranty history",
+		Long:  "Show the prices and warranty a product had in every recorded run, or with --changed-since list the products whose prices moved since the date.",
+		Args:  cobra.MaximumNArgs(1),
+		Run:   historyCommand.Run,
+	}
+	historyCmd.Flags().String("changed-since", "", "list products whose prices changed since the date (YYYY-MM-DD)")
+	historyCmd.Flags().StringP("output", "o", "table", "output format: table, csv or json")
 
-	rootCmd.AddCommand(warrantyCmd)
+	rootCmd.AddCommand(warrantyCmd, cacheCmd, devCmd, discoverCmd, searchCmd, lookupCmd, historyCmd)
 
-	if err := rootCmd.Execute(); err != nil {
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		fmt.Println(err)
+		stop()
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,72 @@
+package command
+
+import (
+	"dniprom-cli/internal/container"
+	"dniprom-cli/internal/service/cache"
+	"dniprom-cli/pkg/logger"
+	"fmt"
+	"github.com/spf13/cobra"
+	"text/tabwriter"
+)
+
+type CacheCommand struct {
+	container container.Container
+	cache     cache.Cache
+}
+
+func NewCacheCommand(container container.Container, cache cache.Cache) *CacheCommand {
+	return &CacheCommand{
+		container: container,
+		cache:     cache,
+	}
+}
+
+func (c *CacheCommand) Clear(cmd *cobra.Command, args []string) {
+	log := c.container.GetLogger()
+	if err := c.cache.Clear(); err != nil {
+		log.Error("fail to clear cache", logger.FError(err))
+		return
+	}
+	log.Info("cache cleared", logger.F("dir", c.container.GetConfig().Cache.Dir))
+}
+
+func (c *CacheCommand) Stats(cmd *cobra.Command, args []string) {
+	log := c.container.GetLogger()
+	stats, err := c.cache.Stats()
+	if err != nil {
+		log.Error("fail to collect cache stats", logger.FError(err))
+		return
+	}
+
+	writer := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "ENDPOINT\tENTRIES\tEXPIRED\tSIZE")
+	var total cache.EndpointStats
+	for _, endpointStats := range stats {
+		_, _ = fmt.Fprintf(
+			writer,
+			"%s\t%d\t%d\t%s\n",
+			endpointStats.Endpoint,
+			endpointStats.Entries,
+			endpointStats.Expired,
+			formatBytes(endpointStats.Bytes),
+		)
+		total.Entries += endpointStats.Entries
+		total.Expired += endpointStats.Expired
+		total.Bytes += endpointStats.Bytes
+	}
+	_, _ = fmt.Fprintf(writer, "total\t%d\t%d\t%s\n", total.Entries, total.Expired, formatBytes(total.Bytes))
+	_ = writer.Flush()
+}
+
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
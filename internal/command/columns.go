@@ -0,0 +1,342 @@
+package command
+
+import (
+	"dniprom-cli/internal/model"
+	"dniprom-cli/internal/model/app"
+	"dniprom-cli/internal/service/recorder"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type columnFormat string
+
+const priceNumberFormat = "0.00"
+
+const (
+	columnFormatText   columnFormat = "text"
+	columnFormatNumber columnFormat = "number"
+	columnFormatLink   columnFormat = "link"
+)
+
+// warrantyRow is everything a column may render for a product.
+type warrantyRow struct {
+	Code            string
+	ProductWarranty *app.ProductWarranty
+	Change          app.ChangeType
+}
+
+// cellValue is the raw content of a cell. The column format decides whether
+// the number or the link is used.
+type cellValue struct {
+	text            string
+	number          *float64
+	link            string
+	backgroundColor *recorder.Color
+}
+
+type columnDefinition struct {
+	label         string
+	defaultFormat columnFormat
+	// xlsxFormat, when set, replaces defaultFormat in XLSX output.
+	xlsxFormat columnFormat
+	// numberFormat is the spreadsheet pattern used with the number format.
+	numberFormat string
+	// enrichment is the product detail the column needs, if any.
+	enrichment app.EnrichmentField
+	value      func(row warrantyRow) cellValue
+}
+
+// columnDefinitions lists every column the warranty output can render, keyed
+// by the name used in the columns config.
+var columnDefinitions = map[string]columnDefinition{
+	"id": {
+		label: "ID",
+		value: func(row warrantyRow) cellValue {
+			id := float64(row.ProductWarranty.ID)
+			return cellValue{text: strconv.FormatInt(row.ProductWarranty.ID, 10), number: &id}
+		},
+	},
+	"code": {
+		label: "Product Code",
+		value: func(row warrantyRow) cellValue {
+			return cellValue{text: row.Code}
+		},
+	},
+	"title": {
+		label: "Title",
+		value: func(row warrantyRow) cellValue {
+			return cellValue{text: row.ProductWarranty.Title}
+		},
+	},
+	"title_uk": titleColumn("uk"),
+	"title_ru": titleColumn("ru"),
+	"title_en": titleColumn("en"),
+	"category": {
+		label:      "Category",
+		enrichment: app.EnrichmentCategory,
+		value: func(row warrantyRow) cellValue {
+			return cellValue{text: strings.Join(row.ProductWarranty.Details.CategoryPath, " > ")}
+		},
+	},
+	"brand": {
+		label:      "Brand",
+		enrichment: app.EnrichmentBrand,
+		value: func(row warrantyRow) cellValue {
+			return cellValue{text: row.ProductWarranty.Details.Brand}
+		},
+	},
+	"availability": {
+		label:      "Availability",
+		enrichment: app.EnrichmentAvailability,
+		value: func(row warrantyRow) cellValue {
+			return cellValue{text: row.ProductWarranty.Details.Availability}
+		},
+	},
+	"url": {
+		label:         "Product URL",
+		defaultFormat: columnFormatLink,
+		enrichment:    app.EnrichmentURL,
+		value: func(row warrantyRow) cellValue {
+			url := row.ProductWarranty.Details.URL
+			return cellValue{text: url, link: url}
+		},
+	},
+	"image": {
+		label:         "Image URL",
+		defaultFormat: columnFormatLink,
+		enrichment:    app.EnrichmentImage,
+		value: func(row warrantyRow) cellValue {
+			url := row.ProductWarranty.Details.ImageURL
+			return cellValue{text: url, link: url}
+		},
+	},
+	"rating": {
+		label:         "Rating",
+		defaultFormat: columnFormatNumber,
+		enrichment:    app.EnrichmentRating,
+		value: func(row warrantyRow) cellValue {
+			rating := row.ProductWarranty.Details.Rating
+			if rating == nil {
+				return cellValue{}
+			}
+			return cellValue{text: fmt.Sprintf("%.1f", *rating), number: rating}
+		},
+	},
+	"warranty": {
+		label: "Warranty",
+		value: func(row warrantyRow) cellValue {
+			return cellValue{text: row.ProductWarranty.WarrantyText}
+		},
+	},
+	"warranty_months": {
+		label:         "Warranty Months",
+		defaultFormat: columnFormatNumber,
+		value: func(row warrantyRow) cellValue {
+			duration := row.ProductWarranty.WarrantyDuration
+			return monthsCellValue(duration.BaseMonths, duration.Parsed)
+		},
+	},
+	"extended_months": {
+		label:         "Extended Months",
+		defaultFormat: columnFormatNumber,
+		value: func(row warrantyRow) cellValue {
+			duration := row.ProductWarranty.WarrantyDuration
+			return monthsCellValue(duration.ExtendedMonths, duration.Parsed)
+		},
+	},
+	"warranty_conditions": {
+		label: "Warranty Conditions",
+		value: func(row warrantyRow) cellValue {
+			return cellValue{text: row.ProductWarranty.WarrantyDuration.Conditions}
+		},
+	},
+	"service_notes": {
+		label: "Service Notes",
+		value: func(row warrantyRow) cellValue {
+			return cellValue{text: row.ProductWarranty.ServiceNotes}
+		},
+	},
+	"new_price": {
+		label:        "New Price",
+		xlsxFormat:   columnFormatNumber,
+		numberFormat: priceNumberFormat,
+		value: func(row warrantyRow) cellValue {
+			return cellValue{text: row.ProductWarranty.NewPrice, number: row.ProductWarranty.NewPriceAmount}
+		},
+	},
+	"old_price": {
+		label:        "Old Price",
+		xlsxFormat:   columnFormatNumber,
+		numberFormat: priceNumberFormat,
+		value: func(row warrantyRow) cellValue {
+			return cellValue{text: row.ProductWarranty.OldPrice, number: row.ProductWarranty.OldPriceAmount}
+		},
+	},
+	"status": {
+		label: "Status",
+		value: func(row warrantyRow) cellValue {
+			return cellValue{text: string(row.ProductWarranty.Status)}
+		},
+	},
+	"change": {
+		label: "Change",
+		value: func(row warrantyRow) cellValue {
+			value := cellValue{text: string(row.Change)}
+			if color, ok := changeColors[row.Change]; ok {
+				value.backgroundColor = &color
+			}
+			return value
+		},
+	},
+}
+
+func titleColumn(language string) columnDefinition {
+	return columnDefinition{
+		label: fmt.Sprintf("Title (%s)", strings.ToUpper(language)),
+		value: func(row warrantyRow) cellValue {
+			return cellValue{text: row.ProductWarranty.Names.Get(language)}
+		},
+	}
+}
+
+func monthsCellValue(months int, parsed bool) cellValue {
+	if !parsed {
+		return cellValue{}
+	}
+	number := float64(months)
+	return cellValue{text: strconv.Itoa(months), number: &number}
+}
+
+type column struct {
+	key        string
+	label      string
+	format     columnFormat
+	bold       bool
+	definition columnDefinition
+}
+
+// resolveColumns builds the output columns from the columns config. Without
+// one, the columns follow the title and enrichment settings.
+func resolveColumns(config *model.Config, output outputFormat) ([]column, error) {
+	columnConfigs := config.Columns
+	if len(columnConfigs) == 0 {
+		columnConfigs = defaultColumnConfigs(config)
+	}
+	columns := make([]column, 0, len(columnConfigs))
+	for _, columnConfig := range columnConfigs {
+		key := strings.ToLower(strings.TrimSpace(columnConfig.Key))
+		definition, ok := columnDefinitions[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q", columnConfig.Key)
+		}
+		format := columnFormat(strings.ToLower(strings.TrimSpace(columnConfig.Format)))
+		switch format {
+		case "":
+			format = definition.defaultFormat
+			if output == outputXLSX && definition.xlsxFormat != "" {
+				format = definition.xlsxFormat
+			}
+		case columnFormatText, columnFormatNumber, columnFormatLink:
+		default:
+			return nil, fmt.Errorf("unknown format %q of column %q", columnConfig.Format, columnConfig.Key)
+		}
+		label := columnConfig.Label
+		if label == "" {
+			label = definition.label
+		}
+		columns = append(columns, column{
+			key:        key,
+			label:      label,
+			format:     format,
+			bold:       columnConfig.Bold,
+			definition: definition,
+		})
+	}
+	return columns, nil
+}
+
+func defaultColumnConfigs(config *model.Config) []model.ColumnConfig {
+	keys := []string{"id", "code", "title"}
+	if config.Title.AllLanguages {
+		for _, language := range config.Title.Languages {
+			keys = append(keys, "title_"+language)
+		}
+	}
+	for _, value := range config.Enrichment {
+		field, err := app.ParseEnrichmentField(strings.ToLower(strings.TrimSpace(value)))
+		if err != nil {
+			continue
+		}
+		for key, definition := range columnDefinitions {
+			if definition.enrichment == field {
+				keys = append(keys, key)
+			}
+		}
+	}
+	keys = append(
+		keys,
+		"warranty",
+		"warranty_months",
+		"extended_months",
+		"warranty_conditions",
+		"service_notes",
+		"new_price",
+		"old_price",
+		"status",
+		"change",
+	)
+	columnConfigs := make([]model.ColumnConfig, 0, len(keys))
+	for _, key := range keys {
+		columnConfigs = append(columnConfigs, model.ColumnConfig{Key: key})
+	}
+	return columnConfigs
+}
+
+// requireColumnEnrichment adds the product details needed by the columns to
+// the enrichment config, so that selecting a column is enough to collect it.
+func requireColumnEnrichment(config *model.Config, columns []column) {
+	for _, column := range columns {
+		field := column.definition.enrichment
+		if field == "" || slices.Contains(config.Enrichment, string(field)) {
+			continue
+		}
+		config.Enrichment = append(config.Enrichment, string(field))
+	}
+}
+
+func headerRichTexts(columns []column, backgroundColor *recorder.Color) []recorder.RichText {
+	richTexts := make([]recorder.RichText, 0, len(columns))
+	for _, column := range columns {
+		richTexts = append(richTexts, recorder.RichText{
+			Value:           column.label,
+			IsBold:          true,
+			BackgroundColor: backgroundColor,
+			LinkColumn:      column.format == columnFormatLink,
+		})
+	}
+	return richTexts
+}
+
+func rowRichTexts(columns []column, row warrantyRow) []recorder.RichText {
+	richTexts := make([]recorder.RichText, 0, len(columns))
+	for _, column := range columns {
+		value := column.definition.value(row)
+		richText := recorder.RichText{
+			Value:           value.text,
+			IsBold:          column.bold,
+			BackgroundColor: value.backgroundColor,
+		}
+		switch column.format {
+		case columnFormatNumber:
+			richText.Number = value.number
+			richText.NumberFormat = column.definition.numberFormat
+		case columnFormatLink:
+			richText.Link = value.link
+			richText.LinkColumn = true
+		}
+		richTexts = append(richTexts, richText)
+	}
+	return richTexts
+}
@@ -0,0 +1,45 @@
+package command
+
+import (
+	"dniprom-cli/internal/model"
+	"testing"
+)
+
+// Prices are numeric cells by default in XLSX only, other outputs keep the
+// formatted text.
+func TestResolveColumnsPriceFormat(t *testing.T) {
+	tests := []struct {
+		name       string
+		output     outputFormat
+		columns    []model.ColumnConfig
+		wantNumber bool
+	}{
+		{name: "sheets default", output: outputSheets},
+		{name: "csv default", output: outputCSV},
+		{name: "xlsx default", output: outputXLSX, wantNumber: true},
+		{
+			name:       "csv explicit number",
+			output:     outputCSV,
+			columns:    []model.ColumnConfig{{Key: "new_price", Format: "number"}},
+			wantNumber: true,
+		},
+		{
+			name:    "xlsx explicit text",
+			output:  outputXLSX,
+			columns: []model.ColumnConfig{{Key: "new_price", Format: "text"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columns, err := resolveColumns(&model.Config{Columns: tt.columns}, tt.output)
+			if err != nil {
+				t.Fatalf("resolveColumns: %v", err)
+			}
+			for _, column := range columns {
+				if column.key == "new_price" && (column.format == columnFormatNumber) != tt.wantNumber {
+					t.Errorf("new_price format = %q, want number %v", column.format, tt.wantNumber)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package command
+
+import (
+	"context"
+	"dniprom-cli/internal/model"
+	"github.com/spf13/cobra"
+)
+
+// runContext derives the context of a command run, applying the --timeout
+// flag when it is set.
+func runContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	ctx := cmd.Context()
+	timeout, err := cmd.Flags().GetDuration("timeout")
+	if err != nil || timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// applyEnrichmentFlag overrides the configured enrichment fields with the
+// --enrich flag when it is passed.
+func applyEnrichmentFlag(cmd *cobra.Command, config *model.Config) {
+	if cmd.Flags().Changed("enrich") {
+		config.Enrichment, _ = cmd.Flags().GetStringSlice("enrich")
+	}
+}
@@ -0,0 +1,61 @@
+package command
+
+import (
+	"context"
+	"dniprom-cli/internal/container"
+	"dniprom-cli/internal/fakeserver"
+	"dniprom-cli/pkg/logger"
+	"errors"
+	"github.com/spf13/cobra"
+	"net"
+	"net/http"
+	"time"
+)
+
+type DevCommand struct {
+	container container.Container
+}
+
+func NewDevCommand(container container.Container) *DevCommand {
+	return &DevCommand{
+		container: container,
+	}
+}
+
+func (d *DevCommand) FakeServer(cmd *cobra.Command, args []string) {
+	log := d.container.GetLogger()
+	ctx := cmd.Context()
+	catalogPath, _ := cmd.Flags().GetString("catalog")
+	addr, _ := cmd.Flags().GetString("addr")
+
+	catalog, err := fakeserver.LoadCatalog(catalogPath)
+	if err != nil {
+		log.Error("fail to load fake catalog", logger.F("path", catalogPath), logger.FError(err))
+		return
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Error("fail to listen", logger.F("addr", addr), logger.FError(err))
+		return
+	}
+	server := &http.Server{
+		Handler:           fakeserver.NewHandler(catalog),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	log.Info(
+		"fake Dnipro-M server is listening",
+		logger.F("baseURL", "http://"+listener.Addr().String()+"/"),
+		logger.F("products", len(catalog.Products)),
+	)
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Error("fake server stopped", logger.FError(err))
+	}
+}
@@ -0,0 +1,123 @@
+package command
+
+import (
+	"dniprom-cli/internal/client"
+	"dniprom-cli/internal/container"
+	"dniprom-cli/internal/model"
+	"dniprom-cli/internal/model/app"
+	"dniprom-cli/internal/worker"
+	"dniprom-cli/pkg/logger"
+	"fmt"
+	"github.com/spf13/cobra"
+	"sort"
+)
+
+type DiscoverCommand struct {
+	container    container.Container
+	dniproClient client.DniproClient
+}
+
+func NewDiscoverCommand(container container.Container, client client.DniproClient) *DiscoverCommand {
+	return &DiscoverCommand{
+		container:    container,
+		dniproClient: client,
+	}
+}
+
+func (d *DiscoverCommand) Run(cmd *cobra.Command, args []string) {
+	log := d.container.GetLogger()
+	config := d.container.GetConfig()
+	ctx, cancel := runContext(cmd)
+	defer cancel()
+
+	sitemapURL := config.Discover.SitemapURL
+	if cmd.Flags().Changed("sitemap-url") {
+		sitemapURL, _ = cmd.Flags().GetString("sitemap-url")
+	}
+	filter := app.CategoryFilter{
+		Include: config.Discover.Include,
+		Exclude: config.Discover.Exclude,
+	}
+	if cmd.Flags().Changed("include") {
+		filter.Include, _ = cmd.Flags().GetStringSlice("include")
+	}
+	if cmd.Flags().Changed("exclude") {
+		filter.Exclude, _ = cmd.Flags().GetStringSlice("exclude")
+	}
+
+	discoverWorker := worker.NewDiscoverWorker(d.container, d.dniproClient)
+	products, err := discoverWorker.DiscoverBySitemap(ctx, sitemapURL)
+	if err != nil {
+		// A partial sitemap would report most products as delisted.
+		log.Error("fail to discover products from sitemap", logger.FError(err))
+		return
+	}
+
+	listedCodes := productCodes(products)
+	products, err = discoverWorker.FilterByCategory(ctx, products, filter)
+	if err != nil {
+		log.Error("fail to filter products by category", logger.FError(err))
+		return
+	}
+
+	codes := productCodes(products)
+	added, _ := worker.DiffCodes(config.ProductCodes, codes)
+	// Products left out by the category filter are still listed, only the
+	// ones missing from the sitemap are delisted.
+	_, removed := worker.DiffCodes(config.ProductCodes, listedCodes)
+	log.Info(
+		"products discovered from sitemap",
+		logger.F("count", len(codes)),
+		logger.F("newlyListed", len(added)),
+		logger.F("delisted", len(removed)),
+	)
+
+	d.writeCodes(cmd, codes)
+
+	productURLs := make(map[string]string, len(products))
+	for _, product := range products {
+		productURLs[product.Code] = product.URL
+	}
+	writer := cmd.OutOrStdout()
+	_, _ = fmt.Fprintf(writer, "Newly listed (%d):\n", len(added))
+	for _, code := range added {
+		_, _ = fmt.Fprintf(writer, "  + %s\t%s\n", code, productURLs[code])
+	}
+	_, _ = fmt.Fprintf(writer, "Delisted (%d):\n", len(removed))
+	for _, code := range removed {
+		_, _ = fmt.Fprintf(writer, "  - %s\n", code)
+	}
+}
+
+// productCodes returns the sorted codes of products.
+func productCodes(products []app.DiscoveredProduct) []string {
+	codes := make([]string, 0, len(products))
+	for _, product := range products {
+		codes = append(codes, product.Code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// writeCodes writes the codes into the --out file and, only when asked with
+// --write-config, into the product_codes of config.yml.
+func (d *DiscoverCommand) writeCodes(cmd *cobra.Command, codes []string) {
+	log := d.container.GetLogger()
+	outFile, _ := cmd.Flags().GetString("out")
+	writeConfig, _ := cmd.Flags().GetBool("write-config")
+
+	if outFile != "" {
+		if err := model.SaveProductCodesFile(outFile, codes); err != nil {
+			log.Error("fail to write codes file", logger.F("path", outFile), logger.FError(err))
+		} else {
+			log.Info("product codes written", logger.F("path", outFile))
+		}
+	}
+	if writeConfig {
+		if err := model.SaveProductCodes(codes); err != nil {
+			log.Error("fail to write product codes into config", logger.FError(err))
+			return
+		}
+		log.Info("product codes written into config", logger.F("path", model.ConfigPath))
+	}
+}
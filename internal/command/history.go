@@ -0,0 +1,193 @@
+package command
+
+import (
+	"dniprom-cli/internal/container"
+	"dniprom-cli/internal/model/app"
+	"dniprom-cli/internal/service/snapshot"
+	"dniprom-cli/internal/worker"
+	"dniprom-cli/pkg/logger"
+	"fmt"
+	"github.com/spf13/cobra"
+	"io"
+	"strings"
+	"time"
+)
+
+type HistoryCommand struct {
+	container container.Container
+}
+
+func NewHistoryCommand(container container.Container) *HistoryCommand {
+	return &HistoryCommand{
+		container: container,
+	}
+}
+
+type historyPoint struct {
+	RunID          string            `json:"run_id"`
+	RecordedAt     time.Time         `json:"recorded_at"`
+	Title          string            `json:"title"`
+	NewPrice       *float64          `json:"new_price"`
+	OldPrice       *float64          `json:"old_price"`
+	Warranty       string            `json:"warranty"`
+	WarrantyMonths *int              `json:"warranty_months"`
+	ExtendedMonths *int              `json:"extended_months"`
+	Status         app.ProductStatus `json:"status"`
+}
+
+type priceChange struct {
+	Code        string    `json:"code"`
+	Title       string    `json:"title"`
+	Since       time.Time `json:"since"`
+	ChangedAt   time.Time `json:"changed_at"`
+	NewPriceWas *float64  `json:"new_price_was"`
+	NewPrice    *float64  `json:"new_price"`
+	OldPriceWas *float64  `json:"old_price_was"`
+	OldPrice    *float64  `json:"old_price"`
+}
+
+func (h *HistoryCommand) Run(cmd *cobra.Command, args []string) {
+	log := h.container.GetLogger()
+
+	outputValue, _ := cmd.Flags().GetString("output")
+	format, err := parseOutputFormat(outputValue, outputTable, outputCSV, outputJSON)
+	if err != nil {
+		log.Error("invalid output format", logger.FError(err))
+		return
+	}
+	changedSinceValue, _ := cmd.Flags().GetString("changed-since")
+	if changedSinceValue == "" && len(args) != 1 {
+		log.Error("expected a product code or --changed-since")
+		return
+	}
+
+	snapshotStore, err := snapshot.NewReadOnlyStore(h.container)
+	if err != nil {
+		log.Error("fail to open snapshot store", logger.FError(err))
+		return
+	}
+	defer snapshotStore.Close()
+	historyWorker := worker.NewHistoryWorker(h.container, snapshotStore)
+
+	writer := cmd.OutOrStdout()
+	if changedSinceValue != "" {
+		since, err := parseHistoryDate(changedSinceValue)
+		if err != nil {
+			log.Error("invalid --changed-since", logger.FError(err))
+			return
+		}
+		changes, err := historyWorker.PriceChangesSince(since)
+		if err != nil {
+			return
+		}
+		if err := writePriceChanges(writer, format, changes); err != nil {
+			log.Error("fail to print price changes", logger.FError(err))
+		}
+		return
+	}
+
+	points, err := historyWorker.Timeline(args[0])
+	if err != nil {
+		return
+	}
+	if len(points) == 0 {
+		log.Warn("no snapshots recorded for product", logger.F("productCode", args[0]))
+	}
+	if err := writeHistoryPoints(writer, format, points); err != nil {
+		log.Error("fail to print product history", logger.FError(err))
+	}
+}
+
+func writeHistoryPoints(writer io.Writer, format outputFormat, points []app.PriceHistoryPoint) error {
+	views := make([]historyPoint, 0, len(points))
+	for _, point := range points {
+		view := historyPoint{
+			RunID:      point.RunID,
+			RecordedAt: point.RecordedAt,
+			Title:      point.Title,
+			NewPrice:   point.NewPrice,
+			OldPrice:   point.OldPrice,
+			Warranty:   point.WarrantyText,
+			Status:     point.Status,
+		}
+		if duration := point.WarrantyDuration; duration.Parsed {
+			view.WarrantyMonths = &duration.BaseMonths
+			view.ExtendedMonths = &duration.ExtendedMonths
+		}
+		views = append(views, view)
+	}
+	if format == outputJSON {
+		return writeJSON(writer, views)
+	}
+
+	header := []string{"RECORDED AT", "RUN", "NEW PRICE", "OLD PRICE", "WARRANTY", "WARRANTY MONTHS", "EXTENDED MONTHS", "STATUS"}
+	rows := make([][]string, 0, len(views))
+	for _, view := range views {
+		rows = append(rows, []string{
+			view.RecordedAt.Local().Format(time.DateTime),
+			view.RunID,
+			formatOptionalPrice(view.NewPrice),
+			formatOptionalPrice(view.OldPrice),
+			view.Warranty,
+			formatOptionalInt(view.WarrantyMonths),
+			formatOptionalInt(view.ExtendedMonths),
+			string(view.Status),
+		})
+	}
+	if format == outputCSV {
+		return writeCSV(writer, header, rows)
+	}
+	return writeTable(writer, header, rows)
+}
+
+func writePriceChanges(writer io.Writer, format outputFormat, changes []app.PriceChange) error {
+	views := make([]priceChange, 0, len(changes))
+	for _, change := range changes {
+		views = append(views, priceChange{
+			Code:        change.Code,
+			Title:       change.Title,
+			Since:       change.Since,
+			ChangedAt:   change.ChangedAt,
+			NewPriceWas: change.NewPriceWas,
+			NewPrice:    change.NewPrice,
+			OldPriceWas: change.OldPriceWas,
+			OldPrice:    change.OldPrice,
+		})
+	}
+	if format == outputJSON {
+		return writeJSON(writer, views)
+	}
+
+	header := []string{"CODE", "TITLE", "NEW PRICE WAS", "NEW PRICE", "OLD PRICE WAS", "OLD PRICE", "CHANGED AT"}
+	rows := make([][]string, 0, len(views))
+	for _, view := range views {
+		rows = append(rows, []string{
+			view.Code,
+			view.Title,
+			formatOptionalPrice(view.NewPriceWas),
+			formatOptionalPrice(view.NewPrice),
+			formatOptionalPrice(view.OldPriceWas),
+			formatOptionalPrice(view.OldPrice),
+			view.ChangedAt.Local().Format(time.DateTime),
+		})
+	}
+	if format == outputCSV {
+		return writeCSV(writer, header, rows)
+	}
+	return writeTable(writer, header, rows)
+}
+
+// parseHistoryDate accepts a local date, a local date with time or an
+// RFC 3339 timestamp.
+func parseHistoryDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date, nil
+	}
+	for _, layout := range []string{time.DateOnly, time.DateTime} {
+		if date, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unsupported date %q, expected YYYY-MM-DD", value)
+}
@@ -0,0 +1,78 @@
+package command
+
+import (
+	"dniprom-cli/internal/client"
+	"dniprom-cli/internal/container"
+	"dniprom-cli/internal/worker"
+	"dniprom-cli/pkg/logger"
+	"fmt"
+	"github.com/spf13/cobra"
+	"strconv"
+)
+
+type LookupCommand struct {
+	container    container.Container
+	dniproClient client.DniproClient
+}
+
+func NewLookupCommand(container container.Container, client client.DniproClient) *LookupCommand {
+	return &LookupCommand{
+		container:    container,
+		dniproClient: client,
+	}
+}
+
+func (l *LookupCommand) Run(cmd *cobra.Command, args []string) {
+	log := l.container.GetLogger()
+	ctx, cancel := runContext(cmd)
+	defer cancel()
+
+	outputValue, _ := cmd.Flags().GetString("output")
+	format, err := parseOutputFormat(outputValue, outputTable, outputJSON, outputYAML)
+	if err != nil {
+		log.Error("invalid output format", logger.FError(err))
+		return
+	}
+	applyEnrichmentFlag(cmd, l.container.GetConfig())
+
+	warrantyWorker := worker.NewWarrantyWorker(l.container, l.dniproClient)
+	warrantyPool := worker.NewWarrantyPool(l.container, warrantyWorker)
+	views := make([]productWarrantyView, 0, len(args))
+	for result := range warrantyPool.FetchByCodes(ctx, args) {
+		views = append(views, newProductWarrantyView(result.ProductWarranty, result.Err))
+	}
+	if err := ctx.Err(); err != nil {
+		log.Warn("lookup interrupted", logger.FError(err))
+	}
+
+	writer := cmd.OutOrStdout()
+	switch format {
+	case outputJSON:
+		err = writeJSON(writer, views)
+	case outputYAML:
+		err = writeYAML(writer, views)
+	default:
+		for i, view := range views {
+			if i > 0 {
+				_, _ = fmt.Fprintln(writer)
+			}
+			if err = writeTable(writer, []string{"FIELD", "VALUE"}, view.rows()); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		log.Error("fail to print lookup results", logger.FError(err))
+	}
+}
+
+func formatInt64(value int64) string {
+	return strconv.FormatInt(value, 10)
+}
+
+func formatOptionalInt(value *int) string {
+	if value == nil {
+		return "-"
+	}
+	return strconv.Itoa(*value)
+}
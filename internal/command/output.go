@@ -0,0 +1,79 @@
+package command
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+type outputFormat string
+
+const (
+	outputTable outputFormat = "table"
+	outputJSON  outputFormat = "json"
+	outputYAML  outputFormat = "yaml"
+	outputCSV   outputFormat = "csv"
+	outputXLSX  outputFormat = "xlsx"
+	// outputNDJSON writes one JSON document per line.
+	outputNDJSON outputFormat = "ndjson"
+	// outputSheets records into the configured Google spreadsheet.
+	outputSheets outputFormat = "sheets"
+)
+
+func parseOutputFormat(value string, allowed ...outputFormat) (outputFormat, error) {
+	format := outputFormat(strings.ToLower(strings.TrimSpace(value)))
+	for _, allowedFormat := range allowed {
+		if format == allowedFormat {
+			return format, nil
+		}
+	}
+	names := make([]string, 0, len(allowed))
+	for _, allowedFormat := range allowed {
+		names = append(names, string(allowedFormat))
+	}
+	return "", fmt.Errorf("unsupported output %q, expected one of: %s", value, strings.Join(names, ", "))
+}
+
+func writeJSON(writer io.Writer, value any) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+func writeYAML(writer io.Writer, value any) error {
+	encoder := yaml.NewEncoder(writer)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(value); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+func writeTable(writer io.Writer, header []string, rows [][]string) error {
+	tableWriter := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tableWriter, strings.Join(header, "\t"))
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			// Multi-line cells would break the table layout.
+			cells[i] = strings.ReplaceAll(cell, "\n", " | ")
+		}
+		_, _ = fmt.Fprintln(tableWriter, strings.Join(cells, "\t"))
+	}
+	return tableWriter.Flush()
+}
+
+func writeCSV(writer io.Writer, header []string, rows [][]string) error {
+	csvWriter := csv.NewWriter(writer)
+	if err := csvWriter.Write(header); err != nil {
+		return err
+	}
+	if err := csvWriter.WriteAll(rows); err != nil {
+		return err
+	}
+	return csvWriter.Error()
+}
@@ -0,0 +1,139 @@
+package command
+
+import (
+	"dniprom-cli/internal/model/app"
+	"strings"
+)
+
+// productWarrantyView is the serialized form of app.ProductWarranty used by
+// the JSON and YAML outputs.
+type productWarrantyView struct {
+	ID                 int64               `json:"id" yaml:"id"`
+	Code               string              `json:"code" yaml:"code"`
+	Title              string              `json:"title" yaml:"title"`
+	Names              map[string]string   `json:"names" yaml:"names"`
+	Warranty           string              `json:"warranty" yaml:"warranty"`
+	WarrantyMonths     *int                `json:"warranty_months" yaml:"warranty_months"`
+	ExtendedMonths     *int                `json:"extended_months" yaml:"extended_months"`
+	WarrantyConditions string              `json:"warranty_conditions,omitempty" yaml:"warranty_conditions,omitempty"`
+	Warranties         []warrantyEntryView `json:"warranties" yaml:"warranties"`
+	ServiceNotes       string              `json:"service_notes,omitempty" yaml:"service_notes,omitempty"`
+	NewPrice           *float64            `json:"new_price" yaml:"new_price"`
+	OldPrice           *float64            `json:"old_price" yaml:"old_price"`
+	Details            *productDetailsView `json:"details,omitempty" yaml:"details,omitempty"`
+	Status             app.ProductStatus   `json:"status" yaml:"status"`
+	Error              string              `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+type warrantyEntryView struct {
+	Component      string `json:"component,omitempty" yaml:"component,omitempty"`
+	Term           string `json:"term" yaml:"term"`
+	BaseMonths     *int   `json:"base_months" yaml:"base_months"`
+	ExtendedMonths *int   `json:"extended_months" yaml:"extended_months"`
+	Conditions     string `json:"conditions,omitempty" yaml:"conditions,omitempty"`
+	ServiceNotes   string `json:"service_notes,omitempty" yaml:"service_notes,omitempty"`
+}
+
+type productDetailsView struct {
+	CategoryPath []string `json:"category_path,omitempty" yaml:"category_path,omitempty"`
+	Brand        string   `json:"brand,omitempty" yaml:"brand,omitempty"`
+	URL          string   `json:"url,omitempty" yaml:"url,omitempty"`
+	ImageURL     string   `json:"image_url,omitempty" yaml:"image_url,omitempty"`
+	Availability string   `json:"availability,omitempty" yaml:"availability,omitempty"`
+	Rating       *float64 `json:"rating,omitempty" yaml:"rating,omitempty"`
+}
+
+func newProductWarrantyView(productWarranty *app.ProductWarranty, err error) productWarrantyView {
+	view := productWarrantyView{
+		ID:    productWarranty.ID,
+		Code:  productWarranty.Code,
+		Title: productWarranty.Title,
+		Names: map[string]string{
+			"uk": productWarranty.Names.UK,
+			"ru": productWarranty.Names.RU,
+			"en": productWarranty.Names.EN,
+		},
+		Warranty:           productWarranty.WarrantyText,
+		WarrantyConditions: productWarranty.WarrantyDuration.Conditions,
+		Warranties:         make([]warrantyEntryView, 0, len(productWarranty.Warranties)),
+		ServiceNotes:       productWarranty.ServiceNotes,
+		NewPrice:           productWarranty.NewPriceAmount,
+		OldPrice:           productWarranty.OldPriceAmount,
+		Status:             productWarranty.Status,
+	}
+	if duration := productWarranty.WarrantyDuration; duration.Parsed {
+		view.WarrantyMonths = &duration.BaseMonths
+		view.ExtendedMonths = &duration.ExtendedMonths
+	}
+	for _, warranty := range productWarranty.Warranties {
+		entryView := warrantyEntryView{
+			Component:    warranty.Component,
+			Term:         warranty.Term,
+			Conditions:   warranty.Conditions,
+			ServiceNotes: warranty.ServiceNotes,
+		}
+		if duration := warranty.Duration; duration.Parsed {
+			entryView.BaseMonths = &duration.BaseMonths
+			entryView.ExtendedMonths = &duration.ExtendedMonths
+		}
+		view.Warranties = append(view.Warranties, entryView)
+	}
+	if details := productWarranty.Details; hasProductDetails(details) {
+		view.Details = &productDetailsView{
+			CategoryPath: details.CategoryPath,
+			Brand:        details.Brand,
+			URL:          details.URL,
+			ImageURL:     details.ImageURL,
+			Availability: details.Availability,
+			Rating:       details.Rating,
+		}
+	}
+	if err != nil {
+		view.Error = err.Error()
+	}
+	return view
+}
+
+func hasProductDetails(details app.ProductDetails) bool {
+	return len(details.CategoryPath) > 0 ||
+		details.Brand != "" ||
+		details.URL != "" ||
+		details.ImageURL != "" ||
+		details.Availability != "" ||
+		details.Rating != nil
+}
+
+// rows renders the view as field/value pairs for the table output.
+func (v productWarrantyView) rows() [][]string {
+	rows := [][]string{
+		{"Code", v.Code},
+		{"ID", formatInt64(v.ID)},
+		{"Title", v.Title},
+		{"Name (UK)", v.Names["uk"]},
+		{"Name (RU)", v.Names["ru"]},
+		{"Name (EN)", v.Names["en"]},
+		{"Warranty", v.Warranty},
+		{"Warranty Months", formatOptionalInt(v.WarrantyMonths)},
+		{"Extended Months", formatOptionalInt(v.ExtendedMonths)},
+		{"Warranty Conditions", v.WarrantyConditions},
+		{"Service Notes", v.ServiceNotes},
+		{"New Price", formatOptionalPrice(v.NewPrice)},
+		{"Old Price", formatOptionalPrice(v.OldPrice)},
+	}
+	if v.Details != nil {
+		rows = append(
+			rows,
+			[]string{"Category", strings.Join(v.Details.CategoryPath, " > ")},
+			[]string{"Brand", v.Details.Brand},
+			[]string{"Availability", v.Details.Availability},
+			[]string{"Product URL", v.Details.URL},
+			[]string{"Image URL", v.Details.ImageURL},
+			[]string{"Rating", formatOptionalPrice(v.Details.Rating)},
+		)
+	}
+	rows = append(rows, []string{"Status", string(v.Status)})
+	if v.Error != "" {
+		rows = append(rows, []string{"Error", v.Error})
+	}
+	return rows
+}
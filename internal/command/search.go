@@ -0,0 +1,104 @@
+package command
+
+import (
+	"dniprom-cli/internal/client"
+	"dniprom-cli/internal/container"
+	"dniprom-cli/internal/model/network"
+	"dniprom-cli/pkg/logger"
+	"fmt"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+type SearchCommand struct {
+	container    container.Container
+	dniproClient client.DniproClient
+}
+
+func NewSearchCommand(container container.Container, client client.DniproClient) *SearchCommand {
+	return &SearchCommand{
+		container:    container,
+		dniproClient: client,
+	}
+}
+
+type searchCandidate struct {
+	ID       int64             `json:"id" yaml:"id"`
+	Code     string            `json:"code" yaml:"code"`
+	Names    map[string]string `json:"names" yaml:"names"`
+	PriceNew *float64          `json:"price_new" yaml:"price_new"`
+	PriceOld *float64          `json:"price_old" yaml:"price_old"`
+}
+
+func (s *SearchCommand) Run(cmd *cobra.Command, args []string) {
+	log := s.container.GetLogger()
+	ctx, cancel := runContext(cmd)
+	defer cancel()
+
+	outputValue, _ := cmd.Flags().GetString("output")
+	format, err := parseOutputFormat(outputValue, outputTable, outputJSON)
+	if err != nil {
+		log.Error("invalid output format", logger.FError(err))
+		return
+	}
+
+	query := strings.Join(args, " ")
+	products, err := s.dniproClient.SearchProducts(ctx, query)
+	if err != nil {
+		log.Error("fail to search products", logger.F("query", query), logger.FError(err))
+		return
+	}
+
+	candidates := make([]searchCandidate, 0, len(products))
+	for _, product := range products {
+		candidates = append(candidates, newSearchCandidate(product))
+	}
+
+	writer := cmd.OutOrStdout()
+	switch format {
+	case outputJSON:
+		err = writeJSON(writer, candidates)
+	default:
+		rows := make([][]string, 0, len(candidates))
+		for _, candidate := range candidates {
+			rows = append(rows, []string{
+				fmt.Sprintf("%d", candidate.ID),
+				candidate.Code,
+				candidate.Names["uk"],
+				candidate.Names["ru"],
+				candidate.Names["en"],
+				formatOptionalPrice(candidate.PriceNew),
+				formatOptionalPrice(candidate.PriceOld),
+			})
+		}
+		err = writeTable(
+			writer,
+			[]string{"ID", "CODE", "NAME (UK)", "NAME (RU)", "NAME (EN)", "NEW PRICE", "OLD PRICE"},
+			rows,
+		)
+	}
+	if err != nil {
+		log.Error("fail to print search results", logger.FError(err))
+	}
+}
+
+func newSearchCandidate(product network.Product) searchCandidate {
+	return searchCandidate{
+		ID:   product.ID,
+		Code: product.VendorCode.String(),
+		Names: map[string]string{
+			"uk": product.Name.UK,
+			"ru": product.Name.RU,
+			"en": product.Name.EN,
+		},
+		PriceNew: product.PriceNew.Value,
+		PriceOld: product.PriceOld.Value,
+	}
+}
+
+func formatOptionalPrice(price *float64) string {
+	if price == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", *price)
+}
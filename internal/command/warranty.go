@@ -1,27 +1,42 @@
 package command
 
 import (
+	"context"
 	"dniprom-cli/internal/client"
 	"dniprom-cli/internal/container"
+	"dniprom-cli/internal/model"
+	"dniprom-cli/internal/model/app"
 	"dniprom-cli/internal/service/recorder"
+	"dniprom-cli/internal/service/snapshot"
 	"dniprom-cli/internal/worker"
 	"dniprom-cli/pkg/logger"
-	"fmt"
+	"errors"
 	"github.com/spf13/cobra"
 	"time"
 )
 
+// snapshotBatchSize is the number of product snapshots stored per database
+// transaction.
+const snapshotBatchSize = 100
+
+var changeColors = map[app.ChangeType]recorder.Color{
+	app.ChangeNew:       {Red: 0.79, Green: 0.85, Blue: 0.97},
+	app.ChangeRemoved:   {Red: 0.85, Green: 0.85, Blue: 0.85},
+	app.ChangePriceUp:   {Red: 0.96, Green: 0.8, Blue: 0.8},
+	app.ChangePriceDown: {Red: 0.85, Green: 0.92, Blue: 0.83},
+	app.ChangePrice:     {Red: 0.99, Green: 0.9, Blue: 0.8},
+	app.ChangeWarranty:  {Red: 1, Green: 0.9, Blue: 0.6},
+}
+
 type WarrantyCommand struct {
 	container    container.Container
 	dniproClient client.DniproClient
-	recorder     recorder.Recorder
 }
 
-func NewWarrantyCommand(container container.Container, client client.DniproClient, recorder recorder.Recorder) *WarrantyCommand {
+func NewWarrantyCommand(container container.Container, client client.DniproClient) *WarrantyCommand {
 	return &WarrantyCommand{
 		container:    container,
 		dniproClient: client,
-		recorder:     recorder,
 	}
 
 }
@@ -29,147 +44,256 @@ func NewWarrantyCommand(container container.Container, client client.DniproClien
 func (w *WarrantyCommand) Run(cmd *cobra.Command, args []string) {
 	log := w.container.GetLogger()
 	config := w.container.GetConfig()
-	warrantyWorker := worker.NewWarrantyWorker(w.container, w.dniproClient)
-	delay := time.Second
+	ctx, cancel := runContext(cmd)
+	defer cancel()
+	outputValue, _ := cmd.Flags().GetString("output")
+	format, err := parseOutputFormat(outputValue, outputSheets, outputCSV, outputXLSX, outputJSON, outputNDJSON)
+	if err != nil {
+		log.Error("invalid output format", logger.FError(err))
+		return
+	}
+	outFile, _ := cmd.Flags().GetString("out-file")
+	if (format == outputCSV || format == outputXLSX) && outFile == "" {
+		log.Error("--out-file is required for file outputs", logger.F("output", format))
+		return
+	}
+	applyEnrichmentFlag(cmd, config)
+	columns, err := resolveColumns(config, format)
+	if err != nil {
+		log.Error("invalid columns config", logger.FError(err))
+		return
+	}
+	requireColumnEnrichment(config, columns)
+	configHash, err := config.Hash()
+	if err != nil {
+		log.Warn("fail to hash config", logger.FError(err))
+	}
 
 	startAt := time.Now().UTC()
+	metadata := runMetadata{
+		RunID:       snapshot.NewRunID(startAt),
+		StartedAt:   startAt,
+		ConfigHash:  configHash,
+		ToolVersion: model.ToolVersion(),
+	}
 
-	yellowColor := recorder.Color{
-		Red:   1,
-		Green: 1,
-		Blue:  0,
+	// The output outlives cancellation so that an interrupted run still
+	// records its footer.
+	output, err := w.newOutput(context.WithoutCancel(ctx), format, outFile, columns, metadata.RunID)
+	if err != nil {
+		log.Error("fail to create output", logger.FError(err))
+		return
+	}
+	defer func() {
+		if err := output.Close(); err != nil {
+			log.Error("fail to close output", logger.FError(err))
+		}
+	}()
+	snapshotStore, run := w.startSnapshotRun(startAt)
+	var previousSnapshots map[string]snapshot.Snapshot
+	var previousRun []snapshot.Snapshot
+	if snapshotStore != nil {
+		defer snapshotStore.Close()
+		previousSnapshots, previousRun = w.loadPreviousRun(snapshotStore)
 	}
+	warrantyWorker := worker.NewWarrantyWorker(w.container, w.dniproClient)
+	warrantyPool := worker.NewWarrantyPool(w.container, warrantyWorker)
 
-	//Headers
-	IDHeaderRichText := recorder.RichText{
-		Value:           "ID",
-		IsBold:          true,
-		BackgroundColor: &yellowColor,
-	}
-	CodeHeaderRichText := recorder.RichText{
-		Value:           "Product Code",
-		IsBold:          true,
-		BackgroundColor: &yellowColor,
-	}
-	TitleHeaderRichText := recorder.RichText{
-		Value:           "Title",
-		IsBold:          true,
-		BackgroundColor: &yellowColor,
-	}
-	WarrantyHeaderRichText := recorder.RichText{
-		Value:           "Warranty",
-		IsBold:          true,
-		BackgroundColor: &yellowColor,
-	}
-	NewPriceHeaderRichText := recorder.RichText{
-		Value:           "New Price",
-		IsBold:          true,
-		BackgroundColor: &yellowColor,
-	}
-	OldPriceHeaderRichText := recorder.RichText{
-		Value:           "Old Price",
-		IsBold:          true,
-		BackgroundColor: &yellowColor,
-	}
-	err := w.recorder.PutRich([]recorder.RichText{
-		IDHeaderRichText,
-		CodeHeaderRichText,
-		TitleHeaderRichText,
-		WarrantyHeaderRichText,
-		NewPriceHeaderRichText,
-		OldPriceHeaderRichText,
-	})
+	err = output.WriteHeader()
 	if err != nil {
 		log.Error("fail to record header", logger.FError(err))
 	}
-	for _, productCode := range config.ProductCodes {
-		productWarranty, err := warrantyWorker.FetchByCode(productCode)
-		if err != nil {
+	rateLimitedCount := 0
+	currentCodes := make(map[string]struct{}, len(config.ProductCodes))
+	for _, code := range config.ProductCodes {
+		currentCodes[code] = struct{}{}
+	}
+	var pendingSnapshots []snapshot.Snapshot
+	for result := range warrantyPool.FetchByCodes(ctx, config.ProductCodes) {
+		productCode, productWarranty, err := result.Code, result.ProductWarranty, result.Err
+		metadata.Products++
+		switch {
+		case errors.Is(err, client.ErrRateLimited):
+			metadata.Failed++
+			rateLimitedCount++
+			log.Warn(
+				"rate limited while fetching warranty by code",
+				logger.F("productCode", productCode),
+			)
+		case err != nil:
+			metadata.Failed++
 			log.Error(
 				"fail to fetch warranty by code",
 				logger.FError(err),
 				logger.F("productCode", productCode),
+				logger.F("status", productWarranty.Status),
 			)
 		}
-		IDRichText := recorder.RichText{
-			Value: fmt.Sprintf("%d", productWarranty.ID),
-		}
-		CodeRichText := recorder.RichText{
-			Value: productCode,
-		}
-		TitleRichText := recorder.RichText{
-			Value: productWarranty.Title,
+		if snapshotStore != nil {
+			snapshotItem := snapshot.Snapshot{
+				RunID:      run.ID,
+				Code:       productCode,
+				Index:      result.Index,
+				RecordedAt: time.Now().UTC(),
+				Product:    snapshot.NewProduct(productWarranty),
+			}
+			if err != nil {
+				snapshotItem.Error = err.Error()
+			}
+			pendingSnapshots = append(pendingSnapshots, snapshotItem)
+			if len(pendingSnapshots) >= snapshotBatchSize {
+				w.putSnapshots(snapshotStore, pendingSnapshots)
+				pendingSnapshots = pendingSnapshots[:0]
+			}
 		}
-		WarrantyRichText := recorder.RichText{
-			Value: productWarranty.WarrantyText,
+		change := app.ChangeType("")
+		if previousSnapshots != nil {
+			if previous, ok := previousSnapshots[productCode]; ok {
+				change = worker.DetectChange(&previous, productWarranty)
+			} else {
+				change = worker.DetectChange(nil, productWarranty)
+			}
 		}
-		OldPriceRichText := recorder.RichText{
-			Value: productWarranty.OldPrice,
-		}
-		NewPriceRichText := recorder.RichText{
-			Value: productWarranty.NewPrice,
-		}
-		err = w.recorder.PutRich([]recorder.RichText{
-			IDRichText,
-			CodeRichText,
-			TitleRichText,
-			WarrantyRichText,
-			OldPriceRichText,
-			NewPriceRichText,
-		})
-		if err != nil {
+		writeErr := output.WriteProduct(warrantyRow{
+			Code:            productCode,
+			ProductWarranty: productWarranty,
+			Change:          change,
+		}, err)
+		if writeErr != nil {
 			log.Error(
 				"fail to record product warranty info in row",
-				logger.FError(err),
+				logger.FError(writeErr),
 				logger.F("productCode", productCode),
 			)
 			break
 		}
-		time.Sleep(delay)
 	}
-	endAt := time.Now().UTC()
-	StartAtTextRichText := recorder.RichText{
-		Value: "Start at: ",
+	if snapshotStore != nil {
+		w.putSnapshots(snapshotStore, pendingSnapshots)
+	}
+	if err := ctx.Err(); err != nil {
+		metadata.Interrupted = true
+		log.Warn("warranty collection interrupted", logger.FError(err))
+	} else {
+		// Products dropped from product_codes keep their last known values.
+		for _, removed := range previousRun {
+			if _, ok := currentCodes[removed.Code]; ok {
+				continue
+			}
+			productWarranty := removed.Product.ProductWarranty(removed.Code)
+			err := output.WriteProduct(warrantyRow{
+				Code:            removed.Code,
+				ProductWarranty: &productWarranty,
+				Change:          app.ChangeRemoved,
+			}, nil)
+			if err != nil {
+				log.Error(
+					"fail to record removed product in row",
+					logger.FError(err),
+					logger.F("productCode", removed.Code),
+				)
+				break
+			}
+		}
+	}
+	if rateLimitedCount > 0 {
+		log.Warn(
+			"some products were rate limited, consider lowering concurrency.requests_per_second",
+			logger.F("count", rateLimitedCount),
+		)
+	}
+	metadata.FinishedAt = time.Now().UTC()
+	if snapshotStore != nil {
+		// A run that missed products must not become the baseline of the next
+		// one, see worker.History.LastRunSnapshots.
+		interrupted := metadata.Interrupted || metadata.Products < len(config.ProductCodes)
+		if err := snapshotStore.FinishRun(run.ID, metadata.FinishedAt, interrupted); err != nil {
+			log.Error("fail to finish snapshot run", logger.FError(err), logger.F("runID", run.ID))
+		}
+	}
+	if err := output.WriteFooter(metadata); err != nil {
+		log.Error("fail to record footer info", logger.FError(err))
 	}
-	StartAtValueRichText := recorder.RichText{
-		Value:  startAt.Format(time.DateTime),
-		IsBold: true,
+}
+
+func (w *WarrantyCommand) newOutput(
+	ctx context.Context,
+	format outputFormat,
+	outFile string,
+	columns []column,
+	runID string,
+) (warrantyOutput, error) {
+	switch format {
+	case outputJSON, outputNDJSON:
+		return newJSONOutput(outFile, format == outputNDJSON, runID)
+	case outputCSV:
+		rec, err := recorder.NewCSVRecorder(w.container, outFile)
+		if err != nil {
+			return nil, err
+		}
+		return &recorderOutput{rec: rec, columns: columns}, nil
+	case outputXLSX:
+		rec, err := recorder.NewXLSXRecorder(w.container, outFile)
+		if err != nil {
+			return nil, err
+		}
+		return &recorderOutput{rec: rec, columns: columns}, nil
+	default:
+		rec, err := recorder.NewRecorder(ctx, w.container)
+		if err != nil {
+			return nil, err
+		}
+		// Every spreadsheet write is a BatchUpdate call, they are spaced out
+		// to stay within the Sheets API write quota.
+		return &recorderOutput{rec: rec, columns: columns, delay: time.Second}, nil
 	}
-	EndAtTextRichText := recorder.RichText{
-		Value: "End at: ",
+}
+
+func (w *WarrantyCommand) putSnapshots(snapshotStore snapshot.Store, snapshots []snapshot.Snapshot) {
+	if err := snapshotStore.Put(snapshots...); err != nil {
+		w.container.GetLogger().Error(
+			"fail to store product snapshots",
+			logger.FError(err),
+			logger.F("count", len(snapshots)),
+		)
 	}
-	EndAtValueRichText := recorder.RichText{
-		Value:  endAt.Format(time.DateTime),
-		IsBold: true,
+}
+
+// startSnapshotRun opens the snapshot store and registers a new run. A store
+// that can't be opened only disables snapshots, the run itself goes on.
+func (w *WarrantyCommand) startSnapshotRun(startAt time.Time) (snapshot.Store, snapshot.Run) {
+	log := w.container.GetLogger()
+	if !w.container.GetConfig().Snapshot.Enabled {
+		return nil, snapshot.Run{}
 	}
-	err = w.recorder.PutRich([]recorder.RichText{
-		StartAtTextRichText, StartAtValueRichText,
-	})
+	snapshotStore, err := snapshot.NewStore(w.container)
 	if err != nil {
-		log.Error("fail to record start date info", logger.FError(err))
-		return
+		log.Error("fail to open snapshot store, results won't be kept", logger.FError(err))
+		return nil, snapshot.Run{}
 	}
-	time.Sleep(delay)
-	err = w.recorder.PutRich([]recorder.RichText{
-		EndAtTextRichText, EndAtValueRichText,
-	})
+	run, err := snapshotStore.StartRun(startAt)
 	if err != nil {
-		log.Error("fail to record end date info", logger.FError(err))
-		return
+		log.Error("fail to start snapshot run, results won't be kept", logger.FError(err))
+		_ = snapshotStore.Close()
+		return nil, snapshot.Run{}
 	}
-	time.Sleep(delay)
+	log.Info("snapshot run started", logger.F("runID", run.ID))
+	return snapshotStore, run
+}
 
-	err = w.recorder.PutRich([]recorder.RichText{
-		{
-			Value: "Powered by",
-		},
-		{
-			Value: "iOSmates",
-			Link:  "https://iosmates.com",
-		},
-	})
-	if err != nil {
-		log.Error("fail to record footer info", logger.FError(err))
-		return
+// loadPreviousRun returns the snapshots of the previous run by product code,
+// along with the same snapshots in run order. Both are nil when there is no
+// completed previous run or it can't be read, which leaves the change column
+// empty.
+func (w *WarrantyCommand) loadPreviousRun(snapshotStore snapshot.Store) (map[string]snapshot.Snapshot, []snapshot.Snapshot) {
+	historyWorker := worker.NewHistoryWorker(w.container, snapshotStore)
+	snapshots, err := historyWorker.LastRunSnapshots()
+	if err != nil || snapshots == nil {
+		return nil, nil
+	}
+	byCode := make(map[string]snapshot.Snapshot, len(snapshots))
+	for _, item := range snapshots {
+		byCode[item.Code] = item
 	}
+	return byCode, snapshots
 }
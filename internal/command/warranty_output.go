@@ -0,0 +1,203 @@
+package command
+
+import (
+	"dniprom-cli/internal/model/app"
+	"dniprom-cli/internal/service/recorder"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// warrantyOutput receives the results of a warranty run.
+type warrantyOutput interface {
+	WriteHeader() error
+	WriteProduct(row warrantyRow, err error) error
+	WriteFooter(metadata runMetadata) error
+	Close() error
+}
+
+type runMetadata struct {
+	RunID       string    `json:"run_id"`
+	StartedAt   time.Time `json:"started_at"`
+	FinishedAt  time.Time `json:"finished_at"`
+	ConfigHash  string    `json:"config_hash"`
+	ToolVersion string    `json:"tool_version"`
+	Products    int       `json:"products"`
+	Failed      int       `json:"failed"`
+	Interrupted bool      `json:"interrupted"`
+}
+
+// recorderOutput renders the results through the column schema into a
+// recorder.Recorder.
+type recorderOutput struct {
+	rec     recorder.Recorder
+	columns []column
+	// delay is the minimum time between two writes.
+	delay     time.Duration
+	lastWrite time.Time
+}
+
+// put writes a row, waiting first until delay has passed since the previous
+// write.
+func (o *recorderOutput) put(richTexts []recorder.RichText) error {
+	if wait := o.delay - time.Since(o.lastWrite); wait > 0 {
+		time.Sleep(wait)
+	}
+	err := o.rec.PutRich(richTexts)
+	o.lastWrite = time.Now()
+	return err
+}
+
+func (o *recorderOutput) WriteHeader() error {
+	yellowColor := recorder.Color{
+		Red:   1,
+		Green: 1,
+		Blue:  0,
+	}
+	return o.put(headerRichTexts(o.columns, &yellowColor))
+}
+
+func (o *recorderOutput) WriteProduct(row warrantyRow, _ error) error {
+	return o.put(rowRichTexts(o.columns, row))
+}
+
+func (o *recorderOutput) WriteFooter(metadata runMetadata) error {
+	StartAtTextRichText := recorder.RichText{
+		Value: "Start at: ",
+	}
+	StartAtValueRichText := recorder.RichText{
+		Value:  metadata.StartedAt.Format(time.DateTime),
+		IsBold: true,
+	}
+	EndAtTextRichText := recorder.RichText{
+		Value: "End at: ",
+	}
+	EndAtValueRichText := recorder.RichText{
+		Value:  metadata.FinishedAt.Format(time.DateTime),
+		IsBold: true,
+	}
+	if footerRecorder, ok := o.rec.(recorder.FooterRecorder); ok {
+		footerRecorder.StartFooter()
+	}
+	err := o.put([]recorder.RichText{
+		StartAtTextRichText, StartAtValueRichText,
+	})
+	if err != nil {
+		return err
+	}
+	err = o.put([]recorder.RichText{
+		EndAtTextRichText, EndAtValueRichText,
+	})
+	if err != nil {
+		return err
+	}
+
+	return o.put([]recorder.RichText{
+		{
+			Value: "Powered by",
+		},
+		{
+			Value: "iOSmates",
+			Link:  "https://iosmates.com",
+		},
+	})
+}
+
+func (o *recorderOutput) Close() error {
+	return o.rec.Close()
+}
+
+type warrantyRecord struct {
+	productWarrantyView
+	Change app.ChangeType `json:"change,omitempty"`
+}
+
+// ndjsonLine is a line of the NDJSON output. Every product is streamed as it
+// is collected and the run metadata comes last.
+type ndjsonLine struct {
+	Type    string          `json:"type"`
+	RunID   string          `json:"run_id"`
+	Product *warrantyRecord `json:"product,omitempty"`
+	Run     *runMetadata    `json:"run,omitempty"`
+}
+
+// jsonOutput writes the results as a single JSON document, or as NDJSON when
+// stream is set.
+type jsonOutput struct {
+	writer   io.Writer
+	closer   io.Closer
+	stream   bool
+	runID    string
+	products []warrantyRecord
+}
+
+// newJSONOutput writes into path, or into stdout when path is empty or "-".
+func newJSONOutput(path string, stream bool, runID string) (*jsonOutput, error) {
+	output := &jsonOutput{
+		writer:   os.Stdout,
+		stream:   stream,
+		runID:    runID,
+		products: []warrantyRecord{},
+	}
+	if path == "" || path == "-" {
+		return output, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	output.writer = file
+	output.closer = file
+	return output, nil
+}
+
+func (o *jsonOutput) WriteHeader() error {
+	return nil
+}
+
+func (o *jsonOutput) WriteProduct(row warrantyRow, err error) error {
+	record := warrantyRecord{
+		productWarrantyView: newProductWarrantyView(row.ProductWarranty, err),
+		Change:              row.Change,
+	}
+	// The code of a failed lookup is only known from the row.
+	record.Code = row.Code
+	if !o.stream {
+		o.products = append(o.products, record)
+		return nil
+	}
+	return json.NewEncoder(o.writer).Encode(ndjsonLine{
+		Type:    "product",
+		RunID:   o.runID,
+		Product: &record,
+	})
+}
+
+func (o *jsonOutput) WriteFooter(metadata runMetadata) error {
+	if o.stream {
+		return json.NewEncoder(o.writer).Encode(ndjsonLine{
+			Type:  "run",
+			RunID: o.runID,
+			Run:   &metadata,
+		})
+	}
+	return writeJSON(o.writer, struct {
+		Run      runMetadata      `json:"run"`
+		Products []warrantyRecord `json:"products"`
+	}{
+		Run:      metadata,
+		Products: o.products,
+	})
+}
+
+func (o *jsonOutput) Close() error {
+	if o.closer == nil {
+		return nil
+	}
+	return o.closer.Close()
+}
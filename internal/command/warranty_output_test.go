@@ -0,0 +1,46 @@
+package command
+
+import (
+	"dniprom-cli/internal/service/recorder"
+	"testing"
+	"time"
+)
+
+type timedRecorder struct {
+	writes []time.Time
+}
+
+func (r *timedRecorder) PutRich([]recorder.RichText) error {
+	r.writes = append(r.writes, time.Now())
+	return nil
+}
+
+func (r *timedRecorder) Close() error {
+	return nil
+}
+
+// Every row is spaced out, not only the footer.
+func TestRecorderOutputSpacesWrites(t *testing.T) {
+	const delay = 20 * time.Millisecond
+	rec := &timedRecorder{}
+	output := &recorderOutput{rec: rec, delay: delay}
+	if err := output.WriteHeader(); err != nil {
+		t.Fatalf("WriteHeader: %v", err)
+	}
+	for range 3 {
+		if err := output.WriteProduct(warrantyRow{}, nil); err != nil {
+			t.Fatalf("WriteProduct: %v", err)
+		}
+	}
+	if err := output.WriteFooter(runMetadata{}); err != nil {
+		t.Fatalf("WriteFooter: %v", err)
+	}
+	if len(rec.writes) != 7 {
+		t.Fatalf("writes = %d, want 7", len(rec.writes))
+	}
+	for i := 1; i < len(rec.writes); i++ {
+		if gap := rec.writes[i].Sub(rec.writes[i-1]); gap < delay {
+			t.Errorf("gap before write %d = %v, want at least %v", i, gap, delay)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package command
+
+import (
+	"dniprom-cli/internal/container"
+	"dniprom-cli/internal/model"
+	"dniprom-cli/internal/service/snapshot"
+	"dniprom-cli/pkg/logger"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Without a completed previous run there is no baseline, so the change column
+// stays empty instead of marking every product as new.
+func TestLoadPreviousRunWithoutBaseline(t *testing.T) {
+	config := &model.Config{
+		Snapshot: model.SnapshotConfig{
+			Enabled: true,
+			Path:    filepath.Join(t.TempDir(), "snapshots.db"),
+		},
+	}
+	c := container.NewContainer(logger.NewNopLogger(), config)
+	snapshotStore, err := snapshot.NewStore(c)
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	defer snapshotStore.Close()
+	// The current run is not finished yet.
+	if _, err := snapshotStore.StartRun(time.Now()); err != nil {
+		t.Fatalf("StartRun: %v", err)
+	}
+
+	byCode, snapshots := NewWarrantyCommand(c, nil).loadPreviousRun(snapshotStore)
+	if byCode != nil || snapshots != nil {
+		t.Errorf("loadPreviousRun() = %v, %v, want nil, nil", byCode, snapshots)
+	}
+}
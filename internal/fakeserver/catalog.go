@@ -0,0 +1,69 @@
+package fakeserver
+
+import (
+	"gopkg.in/yaml.v3"
+	"os"
+	"time"
+)
+
+// Catalog describes the products served by the fake Dnipro-M server and how
+// badly the server should behave. JSON catalogs are accepted as well, since
+// JSON is valid YAML.
+type Catalog struct {
+	// Latency is added to every response.
+	Latency time.Duration `yaml:"latency"`
+	// ErrorRate is the probability, in range [0, 1], of answering with 500.
+	ErrorRate float64 `yaml:"error_rate"`
+	// RateLimitRate is the probability, in range [0, 1], of answering with 429.
+	RateLimitRate float64 `yaml:"rate_limit_rate"`
+	// RequestsPerSecond answers with 429 once exceeded. Zero means unlimited.
+	RequestsPerSecond float64 `yaml:"requests_per_second"`
+	// RetryAfter is sent as the Retry-After header of 429 responses.
+	RetryAfter int       `yaml:"retry_after"`
+	Products   []Product `yaml:"products"`
+}
+
+type Product struct {
+	ID         int64      `yaml:"id"`
+	VendorCode string     `yaml:"vendor_code"`
+	NameUK     string     `yaml:"name_uk"`
+	NameRU     string     `yaml:"name_ru"`
+	NameEN     string     `yaml:"name_en"`
+	PriceNew   *float64   `yaml:"price_new"`
+	PriceOld   *float64   `yaml:"price_old"`
+	Warranty   []Warranty `yaml:"warranty"`
+	// URL, Image, Brand, Categories, Availability and Rating are published on
+	// the product page, Availability as a schema.org ItemAvailability name
+	// such as InStock.
+	URL          string   `yaml:"url"`
+	Image        string   `yaml:"image"`
+	Brand        string   `yaml:"brand"`
+	Categories   []string `yaml:"categories"`
+	Availability string   `yaml:"availability"`
+	Rating       *float64 `yaml:"rating"`
+	// Status forces every request for this product to answer with the given
+	// HTTP status code.
+	Status int `yaml:"status"`
+	// Latency is added to the search and warranty responses of this product,
+	// on top of the catalog latency.
+	Latency time.Duration `yaml:"latency"`
+}
+
+func LoadCatalog(path string) (*Catalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var catalog Catalog
+	if err := yaml.Unmarshal(data, &catalog); err != nil {
+		return nil, err
+	}
+	return &catalog, nil
+}
+
+type Warranty struct {
+	Component  string `yaml:"component"`
+	Term       string `yaml:"term"`
+	Conditions string `yaml:"conditions"`
+	Service    string `yaml:"service"`
+}
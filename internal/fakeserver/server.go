@@ -0,0 +1,314 @@
+package fakeserver
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"golang.org/x/time/rate"
+	"html"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SearchPath   = "/search/drop-down/"
+	WarrantyPath = "/shop/catalog/get-product-service-maintenance/"
+	SitemapPath  = "/sitemap.xml"
+
+	productSitemapPath = "/sitemap-products.xml"
+	pagesSitemapPath   = "/sitemap-pages.xml"
+)
+
+type server struct {
+	catalog *Catalog
+	limiter *rate.Limiter
+}
+
+// NewHandler returns an http.Handler emulating the Dnipro-M endpoints used by
+// the client.
+func NewHandler(catalog *Catalog) http.Handler {
+	s := &server{catalog: catalog}
+	if catalog.RequestsPerSecond > 0 {
+		s.limiter = rate.NewLimiter(rate.Limit(catalog.RequestsPerSecond), 1)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(SearchPath, s.handleSearch)
+	mux.HandleFunc(WarrantyPath, s.handleWarranty)
+	mux.HandleFunc(SitemapPath, s.handleSitemapIndex)
+	mux.HandleFunc(productSitemapPath, s.handleProductSitemap)
+	mux.HandleFunc(pagesSitemapPath, s.handlePagesSitemap)
+	mux.HandleFunc("/", s.handleProductPage)
+	return s.misbehave(mux)
+}
+
+// NewServer starts an httptest.Server backed by catalog. Its URL with a
+// trailing slash can be used as base_url.
+func NewServer(catalog *Catalog) *httptest.Server {
+	return httptest.NewServer(NewHandler(catalog))
+}
+
+func (s *server) misbehave(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.catalog.Latency > 0 {
+			select {
+			case <-time.After(s.catalog.Latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if s.limiter != nil && !s.limiter.Allow() {
+			s.writeRateLimited(w)
+			return
+		}
+		if s.catalog.RateLimitRate > 0 && rand.Float64() < s.catalog.RateLimitRate {
+			s.writeRateLimited(w)
+			return
+		}
+		if s.catalog.ErrorRate > 0 && rand.Float64() < s.catalog.ErrorRate {
+			writeHTMLError(w, http.StatusInternalServerError)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	query := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("q")))
+	products := make([]map[string]any, 0)
+	for _, product := range s.catalog.Products {
+		if query == "" || !product.matches(query) {
+			continue
+		}
+		if !product.wait(r) {
+			return
+		}
+		if product.Status != 0 {
+			writeHTMLError(w, product.Status)
+			return
+		}
+		products = append(products, product.payload())
+	}
+	writeJSON(w, map[string]any{"products": products})
+}
+
+func (s *server) handleWarranty(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.URL.Query().Get("productId"), 10, 64)
+	if err != nil {
+		writeHTMLError(w, http.StatusBadRequest)
+		return
+	}
+	for _, product := range s.catalog.Products {
+		if product.ID != id {
+			continue
+		}
+		if !product.wait(r) {
+			return
+		}
+		if product.Status != 0 {
+			writeHTMLError(w, product.Status)
+			return
+		}
+		entries := make([]map[string]any, 0, len(product.Warranty))
+		for _, warranty := range product.Warranty {
+			entries = append(entries, map[string]any{
+				"name":       warranty.Component,
+				"warranty":   warranty.Term,
+				"conditions": warranty.Conditions,
+				"service":    warranty.Service,
+			})
+		}
+		writeJSON(w, map[string]any{"warranty": entries})
+		return
+	}
+	writeHTMLError(w, http.StatusNotFound)
+}
+
+func (s *server) handleSitemapIndex(w http.ResponseWriter, r *http.Request) {
+	origin := "http://" + r.Host
+	writeXML(w, fmt.Sprintf(
+		`<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`+
+			`<sitemap><loc>%s%s</loc></sitemap>`+
+			`<sitemap><loc>%s%s</loc></sitemap>`+
+			`</sitemapindex>`,
+		origin, productSitemapPath,
+		origin, pagesSitemapPath,
+	))
+}
+
+func (s *server) handleProductSitemap(w http.ResponseWriter, r *http.Request) {
+	origin := "http://" + r.Host
+	var urls strings.Builder
+	for _, product := range s.catalog.Products {
+		_, _ = fmt.Fprintf(&urls, "<url><loc>%s%s</loc></url>", origin, product.pagePath())
+	}
+	writeXML(w, `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`+urls.String()+`</urlset>`)
+}
+
+// handleProductPage serves a product page carrying the schema.org Product and
+// BreadcrumbList JSON-LD, as product pages of the real site do.
+func (s *server) handleProductPage(w http.ResponseWriter, r *http.Request) {
+	for _, product := range s.catalog.Products {
+		if product.pagePath() != r.URL.Path {
+			continue
+		}
+		if product.Status != 0 {
+			writeHTMLError(w, product.Status)
+			return
+		}
+		origin := "http://" + r.Host
+		productLD, _ := json.Marshal(product.jsonLD(origin))
+		breadcrumbsLD, _ := json.Marshal(product.breadcrumbsJSONLD(origin))
+		w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+		_, _ = fmt.Fprintf(
+			w,
+			"<html><head><title>%s</title>\n"+
+				"<script type=\"application/ld+json\">%s</script>\n"+
+				"<script type=\"application/ld+json\">%s</script>\n"+
+				"</head><body><h1>%s</h1></body></html>",
+			html.EscapeString(product.NameUK),
+			productLD,
+			breadcrumbsLD,
+			html.EscapeString(product.NameUK),
+		)
+		return
+	}
+	writeHTMLError(w, http.StatusNotFound)
+}
+
+func (s *server) handlePagesSitemap(w http.ResponseWriter, r *http.Request) {
+	writeXML(w, fmt.Sprintf(
+		`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"><url><loc>http://%s/ua/about/</loc></url></urlset>`,
+		r.Host,
+	))
+}
+
+func (s *server) writeRateLimited(w http.ResponseWriter) {
+	if s.catalog.RetryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(s.catalog.RetryAfter))
+	}
+	writeHTMLError(w, http.StatusTooManyRequests)
+}
+
+// wait holds the response for the product latency. It reports false when the
+// request is cancelled meanwhile.
+func (p Product) wait(r *http.Request) bool {
+	if p.Latency <= 0 {
+		return true
+	}
+	select {
+	case <-time.After(p.Latency):
+		return true
+	case <-r.Context().Done():
+		return false
+	}
+}
+
+func (p Product) matches(query string) bool {
+	return strings.Contains(strings.ToLower(p.VendorCode), query) ||
+		strings.Contains(strings.ToLower(p.NameUK), query) ||
+		strings.Contains(strings.ToLower(p.NameRU), query) ||
+		strings.Contains(strings.ToLower(p.NameEN), query)
+}
+
+func (p Product) payload() map[string]any {
+	return map[string]any{
+		"id":          p.ID,
+		"vendor_code": p.VendorCode,
+		"name": map[string]string{
+			"uk": p.NameUK,
+			"ru": p.NameRU,
+			"en": p.NameEN,
+		},
+		"price_new": p.PriceNew,
+		"price_old": p.PriceOld,
+	}
+}
+
+func (p Product) pagePath() string {
+	path := p.URL
+	if path == "" {
+		path = "/ua/p/product"
+	}
+	return fmt.Sprintf("%s-%s/", strings.TrimSuffix(path, "/"), p.VendorCode)
+}
+
+func (p Product) jsonLD(origin string) map[string]any {
+	product := map[string]any{
+		"@context": "https://schema.org",
+		"@type":    "Product",
+		"name":     p.NameUK,
+		"sku":      p.VendorCode,
+		"url":      origin + p.pagePath(),
+	}
+	if p.Image != "" {
+		product["image"] = []string{origin + p.Image}
+	}
+	if p.Brand != "" {
+		product["brand"] = map[string]string{"@type": "Brand", "name": p.Brand}
+	}
+	offer := map[string]any{
+		"@type":         "Offer",
+		"url":           origin + p.pagePath(),
+		"priceCurrency": "UAH",
+	}
+	if p.PriceNew != nil {
+		offer["price"] = *p.PriceNew
+	}
+	if p.Availability != "" {
+		offer["availability"] = "https://schema.org/" + p.Availability
+	}
+	product["offers"] = offer
+	if p.Rating != nil {
+		product["aggregateRating"] = map[string]any{
+			"@type":       "AggregateRating",
+			"ratingValue": *p.Rating,
+		}
+	}
+	return product
+}
+
+func (p Product) breadcrumbsJSONLD(origin string) map[string]any {
+	names := append(append([]string{"Головна"}, p.Categories...), p.NameUK)
+	items := make([]map[string]any, 0, len(names))
+	for i, name := range names {
+		item := origin + "/ua/"
+		if i > 0 {
+			item = fmt.Sprintf("%s/ua/c/%d/", origin, i)
+		}
+		if i == len(names)-1 {
+			item = origin + p.pagePath()
+		}
+		items = append(items, map[string]any{
+			"@type":    "ListItem",
+			"position": i + 1,
+			"name":     name,
+			"item":     item,
+		})
+	}
+	return map[string]any{
+		"@context":        "https://schema.org",
+		"@type":           "BreadcrumbList",
+		"itemListElement": items,
+	}
+}
+
+func writeJSON(w http.ResponseWriter, payload any) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	_ = json.NewEncoder(w).Encode(payload)
+}
+
+func writeXML(w http.ResponseWriter, payload string) {
+	w.Header().Set("Content-Type", "application/xml; charset=UTF-8")
+	_, _ = fmt.Fprint(w, xml.Header+payload)
+}
+
+func writeHTMLError(w http.ResponseWriter, statusCode int) {
+	w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+	w.WriteHeader(statusCode)
+	_, _ = fmt.Fprintf(w, "<html><body><h1>%d %s</h1></body></html>", statusCode, http.StatusText(statusCode))
+}
@@ -0,0 +1,16 @@
+package app
+
+// ChangeType classifies a product against the previous run.
+type ChangeType string
+
+const (
+	ChangeNew       ChangeType = "new"
+	ChangeRemoved   ChangeType = "removed"
+	ChangePriceUp   ChangeType = "price up"
+	ChangePriceDown ChangeType = "price down"
+	// ChangePrice covers the price changes that are neither up nor down: a
+	// price appearing or disappearing, or only the old price changing.
+	ChangePrice     ChangeType = "price changed"
+	ChangeWarranty  ChangeType = "warranty changed"
+	ChangeUnchanged ChangeType = "unchanged"
+)
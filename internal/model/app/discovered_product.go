@@ -0,0 +1,53 @@
+package app
+
+import "strings"
+
+type DiscoveredProduct struct {
+	Code string
+	URL  string
+	// CategoryPath is only read from the product page when filtering by
+	// category.
+	CategoryPath []string
+}
+
+// CategoryFilter keeps products whose category path matches any Include term
+// (all products when empty) and drops those matching any Exclude term. Terms
+// are case-insensitive substrings of category names.
+type CategoryFilter struct {
+	Include []string
+	Exclude []string
+}
+
+func (f CategoryFilter) IsEmpty() bool {
+	return len(f.Include) == 0 && len(f.Exclude) == 0
+}
+
+func (f CategoryFilter) Matches(categoryPath []string) bool {
+	for _, term := range f.Exclude {
+		if matchesCategory(term, categoryPath) {
+			return false
+		}
+	}
+	if len(f.Include) == 0 {
+		return true
+	}
+	for _, term := range f.Include {
+		if matchesCategory(term, categoryPath) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesCategory(term string, categoryPath []string) bool {
+	term = strings.ToLower(strings.TrimSpace(term))
+	if term == "" {
+		return false
+	}
+	for _, category := range categoryPath {
+		if strings.Contains(strings.ToLower(category), term) {
+			return true
+		}
+	}
+	return false
+}
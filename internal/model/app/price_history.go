@@ -0,0 +1,28 @@
+package app
+
+import "time"
+
+// PriceHistoryPoint is the state of a product recorded by a single run.
+type PriceHistoryPoint struct {
+	RunID            string
+	RecordedAt       time.Time
+	Title            string
+	OldPrice         *float64
+	NewPrice         *float64
+	WarrantyText     string
+	WarrantyDuration WarrantyDuration
+	Status           ProductStatus
+}
+
+// PriceChange compares the prices of a product before a point in time with
+// its latest known prices.
+type PriceChange struct {
+	Code        string
+	Title       string
+	Since       time.Time
+	ChangedAt   time.Time
+	OldPriceWas *float64
+	OldPrice    *float64
+	NewPriceWas *float64
+	NewPrice    *float64
+}
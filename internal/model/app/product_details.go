@@ -0,0 +1,43 @@
+package app
+
+import "fmt"
+
+type EnrichmentField string
+
+const (
+	EnrichmentCategory     EnrichmentField = "category"
+	EnrichmentBrand        EnrichmentField = "brand"
+	EnrichmentAvailability EnrichmentField = "availability"
+	EnrichmentURL          EnrichmentField = "url"
+	EnrichmentImage        EnrichmentField = "image"
+	EnrichmentRating       EnrichmentField = "rating"
+)
+
+var EnrichmentFields = []EnrichmentField{
+	EnrichmentCategory,
+	EnrichmentBrand,
+	EnrichmentAvailability,
+	EnrichmentURL,
+	EnrichmentImage,
+	EnrichmentRating,
+}
+
+func ParseEnrichmentField(value string) (EnrichmentField, error) {
+	for _, field := range EnrichmentFields {
+		if string(field) == value {
+			return field, nil
+		}
+	}
+	return "", fmt.Errorf("unknown enrichment field %q", value)
+}
+
+// ProductDetails holds optional product information. Only the fields
+// requested for the run are filled in.
+type ProductDetails struct {
+	CategoryPath []string
+	Brand        string
+	URL          string
+	ImageURL     string
+	Availability string
+	Rating       *float64
+}
@@ -1,10 +1,83 @@
 package app
 
+type ProductStatus string
+
+const (
+	ProductStatusOK           ProductStatus = "ok"
+	ProductStatusNotFound     ProductStatus = "not found"
+	ProductStatusNoExactMatch ProductStatus = "no exact match"
+	ProductStatusAmbiguous    ProductStatus = "ambiguous"
+	ProductStatusSchema       ProductStatus = "schema mismatch"
+	ProductStatusRateLimited  ProductStatus = "rate limited"
+	ProductStatusUpstream     ProductStatus = "upstream error"
+	ProductStatusBadResponse  ProductStatus = "bad response"
+	ProductStatusError        ProductStatus = "error"
+)
+
+type LocalizedNames struct {
+	UK string
+	RU string
+	EN string
+}
+
+func (n LocalizedNames) Get(language string) string {
+	switch language {
+	case "uk":
+		return n.UK
+	case "ru":
+		return n.RU
+	case "en":
+		return n.EN
+	}
+	return ""
+}
+
+// Preferred returns the first non-empty name following the languages order.
+func (n LocalizedNames) Preferred(languages []string) string {
+	for _, language := range languages {
+		if name := n.Get(language); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+type WarrantyDuration struct {
+	BaseMonths     int
+	ExtendedMonths int
+	Conditions     string
+	// Parsed is false when no duration could be recognized in the text.
+	Parsed bool
+}
+
+func (d WarrantyDuration) TotalMonths() int {
+	return d.BaseMonths + d.ExtendedMonths
+}
+
+type WarrantyEntry struct {
+	Component    string
+	Term         string
+	Duration     WarrantyDuration
+	Conditions   string
+	ServiceNotes string
+}
+
 type ProductWarranty struct {
 	ID           int64
 	Code         string
 	Title        string
+	Names        LocalizedNames
 	WarrantyText string
-	OldPrice     string
-	NewPrice     string
+	Warranties   []WarrantyEntry
+	// WarrantyDuration comes from the first warranty entry with a
+	// recognizable duration, which is the one covering the product itself.
+	WarrantyDuration WarrantyDuration
+	ServiceNotes     string
+	OldPrice         string
+	NewPrice         string
+	// OldPriceAmount and NewPriceAmount are nil when the price is unknown.
+	OldPriceAmount *float64
+	NewPriceAmount *float64
+	Details        ProductDetails
+	Status         ProductStatus
 }
@@ -1,22 +1,153 @@
 package model
 
 import (
+	"crypto/sha256"
+	"dniprom-cli/pkg/fsx"
 	"dniprom-cli/pkg/logger"
+	"encoding/hex"
+	"errors"
 	"gopkg.in/yaml.v3"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const ConfigPath = "./config.yml"
+
+const (
+	defaultWorkers           = 4
+	defaultRequestsPerSecond = 2
+	defaultBurst             = 1
+	defaultMaxAttempts       = 3
+	defaultBaseBackoff       = 500 * time.Millisecond
+	defaultMaxBackoff        = 10 * time.Second
+	defaultHTTPTimeout       = 10 * time.Second
+	defaultUserAgent         = "GoClient/1.0"
+	defaultMaxIdleConns      = 10
+	defaultCacheDir          = "./cache"
+	defaultSearchCacheTTL    = 24 * time.Hour
+	defaultWarrantyCacheTTL  = 7 * 24 * time.Hour
+	defaultCSVDelimiter      = ","
+	defaultSnapshotPath      = "./data/snapshots.db"
+	defaultSitemapURL        = "sitemap.xml"
+	defaultSitemapCodeRegexp = `(\d{6,})/?$`
 )
 
 type Config struct {
-	ProductCodes      []string `yaml:"product_codes"`
-	BaseURL           string   `yaml:"base_url"`
-	ENV               string   `yaml:"env"`
-	FileID            string   `yaml:"file_id"`
-	GoogleCredentials string   `yaml:"google_credentials"`
+	ProductCodes []string `yaml:"product_codes"`
+	// ProductCodesFile lists more product codes, one per line, e.g. as
+	// written by discover. They are added to ProductCodes.
+	ProductCodesFile  string            `yaml:"product_codes_file"`
+	BaseURL           string            `yaml:"base_url"`
+	ENV               string            `yaml:"env"`
+	FileID            string            `yaml:"file_id"`
+	GoogleCredentials string            `yaml:"google_credentials"`
+	Concurrency       ConcurrencyConfig `yaml:"concurrency"`
+	Retry             RetryConfig       `yaml:"retry"`
+	Cache             CacheConfig       `yaml:"cache"`
+	Fixtures          FixturesConfig    `yaml:"fixtures"`
+	HTTP              HTTPConfig        `yaml:"http"`
+	Title             TitleConfig       `yaml:"title"`
+	// Enrichment lists the optional product details to collect, see
+	// app.EnrichmentFields.
+	Enrichment []string       `yaml:"enrichment"`
+	Discover   DiscoverConfig `yaml:"discover"`
+	Snapshot   SnapshotConfig `yaml:"snapshot"`
+	// Columns selects, orders and labels the warranty output columns. When
+	// empty the default layout is used.
+	Columns []ColumnConfig `yaml:"columns"`
+	CSV     CSVConfig      `yaml:"csv"`
+}
+
+type ConcurrencyConfig struct {
+	Workers           int     `yaml:"workers"`
+	RequestsPerSecond float64 `yaml:"requests_per_second"`
+	Burst             int     `yaml:"burst"`
+}
+
+type RetryConfig struct {
+	MaxAttempts int           `yaml:"max_attempts"`
+	BaseBackoff time.Duration `yaml:"base_backoff"`
+	MaxBackoff  time.Duration `yaml:"max_backoff"`
+	// Jitter is the fraction of the backoff, in range [0, 1], that is randomized.
+	Jitter float64 `yaml:"jitter"`
+}
+
+type CacheConfig struct {
+	Enabled     bool          `yaml:"enabled"`
+	Dir         string        `yaml:"dir"`
+	SearchTTL   time.Duration `yaml:"search_ttl"`
+	WarrantyTTL time.Duration `yaml:"warranty_ttl"`
+}
+
+// FixturesConfig controls recording of Dnipro-M traffic into fixture files and
+// serving it back without network. Replay takes precedence over record.
+type FixturesConfig struct {
+	RecordDir string `yaml:"record_dir"`
+	ReplayDir string `yaml:"replay_dir"`
+}
+
+type HTTPConfig struct {
+	Timeout time.Duration `yaml:"timeout"`
+	// ProxyURL accepts http, https and socks5 schemes. When empty the
+	// HTTP_PROXY/HTTPS_PROXY environment variables are used.
+	ProxyURL  string `yaml:"proxy_url"`
+	UserAgent string `yaml:"user_agent"`
+	// Headers are added to every request, overriding the defaults. An empty
+	// value removes the header.
+	Headers      map[string]string `yaml:"headers"`
+	CABundle     string            `yaml:"ca_bundle"`
+	MaxIdleConns int               `yaml:"max_idle_conns"`
+}
+
+type ColumnConfig struct {
+	Key   string `yaml:"key"`
+	Label string `yaml:"label"`
+	// Format is one of text, number or link. Empty keeps the column default.
+	Format string `yaml:"format"`
+	Bold   bool   `yaml:"bold"`
+}
+
+type CSVConfig struct {
+	Delimiter string `yaml:"delimiter"`
+	// BOM prepends the UTF-8 byte order mark so that Excel detects the
+	// encoding.
+	BOM bool `yaml:"bom"`
+}
+
+// SnapshotConfig controls the local database keeping the results of every
+// warranty run.
+type SnapshotConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Path    string `yaml:"path"`
+}
+
+type TitleConfig struct {
+	// Languages is the preferred order used to pick the product title.
+	Languages []string `yaml:"languages"`
+	// AllLanguages records every localized name as a separate column.
+	AllLanguages bool `yaml:"all_languages"`
+}
+
+type DiscoverConfig struct {
+	// SitemapURL is the sitemap index, absolute or relative to base_url.
+	SitemapURL string `yaml:"sitemap_url"`
+	// ProductSitemaps keeps only child sitemaps whose URL contains this
+	// substring. Empty walks every child sitemap.
+	ProductSitemaps string `yaml:"product_sitemaps"`
+	// CodeRegexp extracts the product code from a product page URL using its
+	// first capture group.
+	CodeRegexp string `yaml:"code_regexp"`
+	// Include and Exclude filter the discovered products by the categories of
+	// their product page breadcrumbs, see app.CategoryFilter. Filtering fetches
+	// every product page.
+	Include []string `yaml:"include"`
+	Exclude []string `yaml:"exclude"`
 }
 
 func LoadConfig() (*Config, error) {
-	path := "./config.yml"
-	data, err := os.ReadFile(path)
+	data, err := os.ReadFile(ConfigPath)
 	if err != nil {
 		return nil, err
 	}
@@ -24,10 +155,179 @@ func LoadConfig() (*Config, error) {
 	if err := yaml.Unmarshal(data, &conf); err != nil {
 		return nil, err
 	}
+	if conf.ProductCodesFile != "" {
+		codes, err := LoadProductCodesFile(conf.ProductCodesFile)
+		if err != nil {
+			return nil, err
+		}
+		conf.ProductCodes = append(conf.ProductCodes, codes...)
+	}
+	conf.setDefaults()
 	return &conf, nil
 }
 
+// LoadProductCodesFile reads one code per line, skipping blank lines and
+// # comments.
+func LoadProductCodesFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var codes []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		codes = append(codes, line)
+	}
+	return codes, nil
+}
+
+func SaveProductCodesFile(path string, codes []string) error {
+	return fsx.WriteFileAtomic(path, []byte(strings.Join(codes, "\n")+"\n"), 0o644)
+}
+
 func (c *Config) GetLoggerENV() logger.ENV {
 	env, _ := logger.ENVFromString(c.ENV)
 	return env
 }
+
+// Hash identifies the effective configuration of a run, flag overrides
+// included.
+func (c *Config) Hash() (string, error) {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func (c *Config) setDefaults() {
+	if c.Concurrency.Workers <= 0 {
+		c.Concurrency.Workers = defaultWorkers
+	}
+	if c.Concurrency.RequestsPerSecond <= 0 {
+		c.Concurrency.RequestsPerSecond = defaultRequestsPerSecond
+	}
+	if c.Concurrency.Burst <= 0 {
+		c.Concurrency.Burst = defaultBurst
+	}
+	if c.Retry.MaxAttempts <= 0 {
+		c.Retry.MaxAttempts = defaultMaxAttempts
+	}
+	if c.Retry.BaseBackoff <= 0 {
+		c.Retry.BaseBackoff = defaultBaseBackoff
+	}
+	if c.Retry.MaxBackoff <= 0 {
+		c.Retry.MaxBackoff = defaultMaxBackoff
+	}
+	if c.Retry.Jitter < 0 {
+		c.Retry.Jitter = 0
+	} else if c.Retry.Jitter > 1 {
+		c.Retry.Jitter = 1
+	}
+	if c.HTTP.Timeout <= 0 {
+		c.HTTP.Timeout = defaultHTTPTimeout
+	}
+	if c.HTTP.UserAgent == "" {
+		c.HTTP.UserAgent = defaultUserAgent
+	}
+	if c.HTTP.MaxIdleConns <= 0 {
+		c.HTTP.MaxIdleConns = defaultMaxIdleConns
+	}
+	if len(c.Title.Languages) == 0 {
+		c.Title.Languages = []string{"uk", "ru", "en"}
+	}
+	for i, language := range c.Title.Languages {
+		c.Title.Languages[i] = strings.ToLower(strings.TrimSpace(language))
+	}
+	if c.Discover.SitemapURL == "" {
+		c.Discover.SitemapURL = defaultSitemapURL
+	}
+	if c.Discover.CodeRegexp == "" {
+		c.Discover.CodeRegexp = defaultSitemapCodeRegexp
+	}
+	if c.CSV.Delimiter == "" {
+		c.CSV.Delimiter = defaultCSVDelimiter
+	}
+	if c.Snapshot.Path == "" {
+		c.Snapshot.Path = defaultSnapshotPath
+	}
+	if c.Cache.Dir == "" {
+		c.Cache.Dir = defaultCacheDir
+	}
+	if c.Cache.SearchTTL <= 0 {
+		c.Cache.SearchTTL = defaultSearchCacheTTL
+	}
+	if c.Cache.WarrantyTTL <= 0 {
+		c.Cache.WarrantyTTL = defaultWarrantyCacheTTL
+	}
+}
+
+// SaveProductCodes replaces the product_codes list of config.yml. Only the
+// lines of that list are rewritten, the rest of the file, comments and
+// formatting included, is kept as is.
+func SaveProductCodes(codes []string) error {
+	data, err := os.ReadFile(ConfigPath)
+	if err != nil {
+		return err
+	}
+	var document yaml.Node
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return err
+	}
+	if len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+		return errors.New("config root is not a mapping")
+	}
+
+	var block strings.Builder
+	block.WriteString("product_codes:\n")
+	for _, code := range codes {
+		// Plain numeric codes are written unquoted, as in the hand-maintained
+		// list; anything else stays a quoted string.
+		if _, err := strconv.ParseUint(code, 10, 64); err != nil || strings.HasPrefix(code, "0") {
+			code = strconv.Quote(code)
+		}
+		block.WriteString("  - " + code + "\n")
+	}
+
+	lines := strings.SplitAfter(string(data), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	root := document.Content[0]
+	start, end := -1, len(lines)
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != "product_codes" {
+			continue
+		}
+		start = root.Content[i].Line - 1
+		if i+2 < len(root.Content) {
+			end = root.Content[i+2].Line - 1
+		}
+		break
+	}
+
+	var content string
+	if start < 0 {
+		content = strings.Join(lines, "")
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		content += block.String()
+	} else {
+		// Keep the comments and blank lines leading to the next key.
+		for end > start+1 && isBlankOrComment(lines[end-1]) {
+			end--
+		}
+		content = strings.Join(lines[:start], "") + block.String() + strings.Join(lines[end:], "")
+	}
+	return fsx.WriteFileAtomic(ConfigPath, []byte(content), 0o644)
+}
+
+func isBlankOrComment(line string) bool {
+	line = strings.TrimSpace(line)
+	return line == "" || strings.HasPrefix(line, "#")
+}
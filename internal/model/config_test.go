@@ -0,0 +1,105 @@
+package model
+
+import (
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestSaveProductCodes(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		codes  []string
+		want   string
+	}{
+		{
+			name: "block list in the middle",
+			config: "# Dnipro-M settings\n" +
+				"base_url: https://dnipro-m.ua/\n" +
+				"product_codes:\n" +
+				"  - 8029001 # drill\n" +
+				"  - 8029002\n" +
+				"\n" +
+				"# Local cache\n" +
+				"cache:\n" +
+				"  enabled: true   # keep\n",
+			codes: []string{"8029001", "0123", "ABC-1"},
+			want: "# Dnipro-M settings\n" +
+				"base_url: https://dnipro-m.ua/\n" +
+				"product_codes:\n" +
+				"  - 8029001\n" +
+				"  - \"0123\"\n" +
+				"  - \"ABC-1\"\n" +
+				"\n" +
+				"# Local cache\n" +
+				"cache:\n" +
+				"  enabled: true   # keep\n",
+		},
+		{
+			name:   "flow list at the end",
+			config: "env: dev\nproduct_codes: [1, 2]\n# trailing comment\n",
+			codes:  []string{"3"},
+			want:   "env: dev\nproduct_codes:\n  - 3\n# trailing comment\n",
+		},
+		{
+			name:   "missing list",
+			config: "env: dev",
+			codes:  []string{"3"},
+			want:   "env: dev\nproduct_codes:\n  - 3\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			if err := os.WriteFile(ConfigPath, []byte(tt.config), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if err := SaveProductCodes(tt.codes); err != nil {
+				t.Fatalf("SaveProductCodes: %v", err)
+			}
+			data, err := os.ReadFile(ConfigPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("config =\n%s\nwant\n%s", data, tt.want)
+			}
+			info, err := os.Stat(ConfigPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Mode().Perm() != 0o600 {
+				t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+			}
+		})
+	}
+}
+
+func TestProductCodesFile(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := SaveProductCodesFile("codes.txt", []string{"8029001", "8029002"}); err != nil {
+		t.Fatalf("SaveProductCodesFile: %v", err)
+	}
+	if err := os.WriteFile(ConfigPath, []byte("product_codes: [8617001]\nproduct_codes_file: codes.txt\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if want := []string{"8617001", "8029001", "8029002"}; !slices.Equal(config.ProductCodes, want) {
+		t.Errorf("ProductCodes = %q, want %q", config.ProductCodes, want)
+	}
+
+	if err := os.WriteFile("codes.txt", []byte("# comment\n\n 8029003 \n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	codes, err := LoadProductCodesFile("codes.txt")
+	if err != nil {
+		t.Fatalf("LoadProductCodesFile: %v", err)
+	}
+	if want := []string{"8029003"}; !slices.Equal(codes, want) {
+		t.Errorf("codes = %q, want %q", codes, want)
+	}
+}
@@ -0,0 +1,26 @@
+package network
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrSchemaMismatch = errors.New("response schema mismatch")
+
+// SchemaMismatchError reports a field that Dnipro-M is expected to return but
+// which is absent from the decoded payload.
+type SchemaMismatchError struct {
+	Path string
+}
+
+func (e *SchemaMismatchError) Error() string {
+	return fmt.Sprintf("%s: missing %q", ErrSchemaMismatch.Error(), e.Path)
+}
+
+func (e *SchemaMismatchError) Is(target error) bool {
+	return target == ErrSchemaMismatch
+}
+
+func missingField(format string, args ...any) error {
+	return &SchemaMismatchError{Path: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,260 @@
+package network
+
+import (
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var jsonLDRegexp = regexp.MustCompile(`(?is)<script[^>]*type\s*=\s*["']application/ld\+json["'][^>]*>(.*?)</script>`)
+
+// ProductPage holds the product details published on the product page as
+// schema.org JSON-LD: a Product and, when present, its BreadcrumbList.
+type ProductPage struct {
+	Name         string
+	SKU          string
+	Brand        string
+	Image        string
+	URL          string
+	Availability string
+	Rating       *float64
+	// CategoryPath is the breadcrumb trail from the root, without the home
+	// page and the product itself.
+	CategoryPath []string
+
+	found bool
+}
+
+// ParseProductPage extracts the schema.org Product and BreadcrumbList from the
+// JSON-LD scripts of a product page. Scripts that are not valid JSON are
+// skipped.
+func ParseProductPage(html []byte) *ProductPage {
+	page := &ProductPage{}
+	var breadcrumbs []ldBreadcrumb
+	for _, match := range jsonLDRegexp.FindAllSubmatch(html, -1) {
+		for _, node := range ldNodes(match[1]) {
+			var typed struct {
+				Type ldStrings `json:"@type"`
+			}
+			if err := json.Unmarshal(node, &typed); err != nil {
+				continue
+			}
+			switch {
+			case typed.Type.contains("Product") && !page.found:
+				var product ldProduct
+				if err := json.Unmarshal(node, &product); err != nil {
+					continue
+				}
+				page.setProduct(product)
+			case typed.Type.contains("BreadcrumbList") && breadcrumbs == nil:
+				var list struct {
+					Items []ldBreadcrumb `json:"itemListElement"`
+				}
+				if err := json.Unmarshal(node, &list); err != nil {
+					continue
+				}
+				breadcrumbs = list.Items
+			}
+		}
+	}
+	page.setCategoryPath(breadcrumbs)
+	return page
+}
+
+func (p *ProductPage) Validate() error {
+	if !p.found {
+		return missingField("ld+json Product")
+	}
+	return nil
+}
+
+func (p *ProductPage) setProduct(product ldProduct) {
+	p.found = true
+	p.Name = strings.TrimSpace(product.Name)
+	p.SKU = strings.TrimSpace(product.SKU.String())
+	p.Brand = strings.TrimSpace(product.Brand.Name)
+	if len(product.Image) > 0 {
+		p.Image = product.Image[0].URL
+	}
+	if len(product.Offers) > 0 {
+		offer := product.Offers[0]
+		p.URL = offer.URL
+		// https://schema.org/InStock is reported as InStock.
+		availability := strings.TrimRight(offer.Availability, "/")
+		p.Availability = availability[strings.LastIndex(availability, "/")+1:]
+	}
+	if product.URL != "" {
+		p.URL = product.URL
+	}
+	if value, err := strconv.ParseFloat(product.AggregateRating.RatingValue.String(), 64); err == nil {
+		p.Rating = &value
+	}
+}
+
+func (p *ProductPage) setCategoryPath(breadcrumbs []ldBreadcrumb) {
+	path := make([]string, 0, len(breadcrumbs))
+	for i, breadcrumb := range breadcrumbs {
+		name := strings.TrimSpace(breadcrumb.Name)
+		if name == "" {
+			name = strings.TrimSpace(breadcrumb.Item.Name)
+		}
+		if name == "" || (i == 0 && breadcrumb.isHome()) {
+			continue
+		}
+		path = append(path, name)
+	}
+	if len(path) > 0 && p.found && path[len(path)-1] == p.Name {
+		path = path[:len(path)-1]
+	}
+	p.CategoryPath = path
+}
+
+// ldNodes returns the top level JSON-LD nodes of a script, flattening arrays
+// and @graph containers.
+func ldNodes(data []byte) []json.RawMessage {
+	var nodes []json.RawMessage
+	if err := json.Unmarshal(data, &nodes); err != nil {
+		nodes = []json.RawMessage{data}
+	}
+	flattened := make([]json.RawMessage, 0, len(nodes))
+	for _, node := range nodes {
+		var graph struct {
+			Graph []json.RawMessage `json:"@graph"`
+		}
+		if err := json.Unmarshal(node, &graph); err == nil && len(graph.Graph) > 0 {
+			flattened = append(flattened, graph.Graph...)
+			continue
+		}
+		flattened = append(flattened, node)
+	}
+	return flattened
+}
+
+type ldProduct struct {
+	Name            string    `json:"name"`
+	SKU             ldScalar  `json:"sku"`
+	URL             string    `json:"url"`
+	Brand           ldNamed   `json:"brand"`
+	Image           []ldNamed `json:"-"`
+	Offers          []ldOffer `json:"-"`
+	AggregateRating struct {
+		RatingValue ldScalar `json:"ratingValue"`
+	} `json:"aggregateRating"`
+}
+
+func (p *ldProduct) UnmarshalJSON(data []byte) error {
+	type plain ldProduct
+	var product struct {
+		plain
+		Image  json.RawMessage `json:"image"`
+		Offers json.RawMessage `json:"offers"`
+	}
+	if err := json.Unmarshal(data, &product); err != nil {
+		return err
+	}
+	*p = ldProduct(product.plain)
+	p.Image = ldList[ldNamed](product.Image)
+	p.Offers = ldList[ldOffer](product.Offers)
+	return nil
+}
+
+type ldOffer struct {
+	URL          string `json:"url"`
+	Availability string `json:"availability"`
+}
+
+type ldBreadcrumb struct {
+	Name string  `json:"name"`
+	Item ldNamed `json:"item"`
+}
+
+// isHome reports whether the breadcrumb links to the site root or to a
+// language root such as /ua/.
+func (b ldBreadcrumb) isHome() bool {
+	ref := b.Item.URL
+	if ref == "" {
+		ref = b.Item.ID
+	}
+	u, err := url.Parse(ref)
+	if err != nil || ref == "" {
+		return false
+	}
+	return len(strings.Trim(u.Path, "/")) <= 2
+}
+
+// ldNamed is a schema.org Thing given either as an object or as a plain
+// string, which is taken as its name and URL.
+type ldNamed struct {
+	ID   string `json:"@id"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+func (n *ldNamed) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		n.Name, n.URL = text, text
+		return nil
+	}
+	type plain ldNamed
+	return json.Unmarshal(data, (*plain)(n))
+}
+
+// ldStrings accepts a single string or an array of strings.
+type ldStrings []string
+
+func (s *ldStrings) UnmarshalJSON(data []byte) error {
+	*s = ldList[string](data)
+	return nil
+}
+
+func (s ldStrings) contains(value string) bool {
+	for _, item := range s {
+		if item == value || strings.HasSuffix(item, "/"+value) {
+			return true
+		}
+	}
+	return false
+}
+
+// ldScalar keeps a string or a number as text.
+type ldScalar string
+
+func (s *ldScalar) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*s = ldScalar(text)
+		return nil
+	}
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err == nil {
+		*s = ldScalar(number.String())
+	}
+	return nil
+}
+
+func (s ldScalar) String() string {
+	return string(s)
+}
+
+// ldList decodes a JSON-LD value that may be given either as a single item or
+// as an array of items. Items that don't decode are skipped.
+func ldList[T any](data []byte) []T {
+	if len(data) == 0 {
+		return nil
+	}
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		raw = []json.RawMessage{data}
+	}
+	items := make([]T, 0, len(raw))
+	for _, item := range raw {
+		var value T
+		if err := json.Unmarshal(item, &value); err == nil {
+			items = append(items, value)
+		}
+	}
+	return items
+}
@@ -0,0 +1,100 @@
+package network
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestParseProductPage(t *testing.T) {
+	data, err := os.ReadFile("testdata/product_page.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	page := ParseProductPage(data)
+	if err := page.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	checks := []struct {
+		field string
+		got   string
+		want  string
+	}{
+		{field: "Name", got: page.Name, want: "Дриль-шуруповерт акумуляторний CD-200BC"},
+		{field: "SKU", got: page.SKU, want: "8617001"},
+		{field: "Brand", got: page.Brand, want: "Dnipro-M"},
+		{field: "Image", got: page.Image, want: "https://dnipro-m.ua/images/products/8617001.jpg"},
+		{field: "URL", got: page.URL, want: "https://dnipro-m.ua/ua/p/drel-shurupovert-akkumulyatornaya-cd-200bc-8617001/"},
+		{field: "Availability", got: page.Availability, want: "InStock"},
+	}
+	for _, check := range checks {
+		if check.got != check.want {
+			t.Errorf("%s = %q, want %q", check.field, check.got, check.want)
+		}
+	}
+	if page.Rating == nil || *page.Rating != 4.8 {
+		t.Errorf("Rating = %v, want 4.8", page.Rating)
+	}
+	if want := []string{"Електроінструмент", "Дрилі-шуруповерти"}; !slices.Equal(page.CategoryPath, want) {
+		t.Errorf("CategoryPath = %q, want %q", page.CategoryPath, want)
+	}
+}
+
+func TestParseProductPageVariants(t *testing.T) {
+	tests := []struct {
+		name      string
+		html      string
+		wantErr   bool
+		wantBrand string
+		wantPath  []string
+	}{
+		{
+			name: "graph with string brand",
+			html: `<script type="application/ld+json">{"@context":"https://schema.org","@graph":[` +
+				`{"@type":"WebSite","name":"Dnipro-M"},` +
+				`{"@type":["Product","Thing"],"name":"Болгарка GS-100","brand":"Dnipro-M","offers":[{"availability":"http://schema.org/OutOfStock"}]},` +
+				`{"@type":"BreadcrumbList","itemListElement":[{"position":1,"item":{"@id":"https://dnipro-m.ua/","name":"Головна"}},{"position":2,"item":{"@id":"https://dnipro-m.ua/ua/c/bolgarky/","name":"Болгарки"}}]}` +
+				`]}</script>`,
+			wantBrand: "Dnipro-M",
+			wantPath:  []string{"Болгарки"},
+		},
+		{
+			name:    "no product",
+			html:    `<script type="application/ld+json">{"@type":"Organization","name":"Dnipro-M"}</script>`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid json",
+			html:    `<script type="application/ld+json">{"@type":"Product",</script>`,
+			wantErr: true,
+		},
+		{
+			name:    "no json-ld",
+			html:    `<html><body>Dnipro-M</body></html>`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := ParseProductPage([]byte(tt.html))
+			err := page.Validate()
+			if tt.wantErr {
+				if !errors.Is(err, ErrSchemaMismatch) {
+					t.Fatalf("Validate() = %v, want schema mismatch", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate: %v", err)
+			}
+			if page.Brand != tt.wantBrand {
+				t.Errorf("Brand = %q, want %q", page.Brand, tt.wantBrand)
+			}
+			if !slices.Equal(page.CategoryPath, tt.wantPath) {
+				t.Errorf("CategoryPath = %q, want %q", page.CategoryPath, tt.wantPath)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package network
+
+// SearchResponse is the payload of the search/drop-down/ endpoint.
+type SearchResponse struct {
+	Products []Product `json:"products"`
+}
+
+func (r *SearchResponse) Validate() error {
+	if r.Products == nil {
+		return missingField("products")
+	}
+	for i, product := range r.Products {
+		if product.ID == 0 {
+			return missingField("products[%d].id", i)
+		}
+		// Exact matching relies on the vendor code, so a payload without it
+		// is drift rather than a product that doesn't match.
+		if product.VendorCode == "" {
+			return missingField("products[%d].vendor_code", i)
+		}
+	}
+	return nil
+}
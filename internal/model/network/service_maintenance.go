@@ -0,0 +1,30 @@
+package network
+
+// ServiceMaintenanceResponse is the payload of the
+// shop/catalog/get-product-service-maintenance/ endpoint.
+type ServiceMaintenanceResponse struct {
+	Warranty []WarrantyEntry `json:"warranty"`
+}
+
+// WarrantyEntry is a single warranty term. Products made of several
+// components (e.g. a tool and its battery) have one entry per component.
+// Only warranty is required, the other keys are optional and left empty when
+// the payload doesn't carry them.
+type WarrantyEntry struct {
+	Component  string  `json:"name"`
+	Warranty   *string `json:"warranty"`
+	Conditions string  `json:"conditions"`
+	Service    string  `json:"service"`
+}
+
+func (r *ServiceMaintenanceResponse) Validate() error {
+	if r.Warranty == nil {
+		return missingField("warranty")
+	}
+	for i, entry := range r.Warranty {
+		if entry.Warranty == nil {
+			return missingField("warranty[%d].warranty", i)
+		}
+	}
+	return nil
+}
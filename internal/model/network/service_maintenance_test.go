@@ -0,0 +1,75 @@
+package network
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+)
+
+func ptr(s string) *string {
+	return &s
+}
+
+func TestServiceMaintenanceResponseDecode(t *testing.T) {
+	data, err := os.ReadFile("testdata/service_maintenance.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var response ServiceMaintenanceResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if err := response.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	want := []WarrantyEntry{
+		{
+			Component:  "Акумуляторний дриль-шуруповерт",
+			Warranty:   ptr("36 місяців"),
+			Conditions: "за умови реєстрації",
+			Service:    "Безкоштовне ТО протягом першого року",
+		},
+		{
+			Component: "Акумулятор",
+			Warranty:  ptr("12 місяців"),
+		},
+	}
+	if len(response.Warranty) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(response.Warranty), len(want))
+	}
+	for i, entry := range response.Warranty {
+		if entry.Component != want[i].Component ||
+			*entry.Warranty != *want[i].Warranty ||
+			entry.Conditions != want[i].Conditions ||
+			entry.Service != want[i].Service {
+			t.Errorf("entry %d = %+v, want %+v", i, entry, want[i])
+		}
+	}
+}
+
+func TestServiceMaintenanceResponseValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{name: "warranty only", body: `{"warranty":[{"warranty":"12 місяців"}]}`},
+		{name: "no entries", body: `{"warranty":[]}`},
+		{name: "missing warranty", body: `{}`, wantErr: true},
+		{name: "missing entry warranty", body: `{"warranty":[{"name":"Акумулятор"}]}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var response ServiceMaintenanceResponse
+			if err := json.Unmarshal([]byte(tt.body), &response); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			err := response.Validate()
+			if tt.wantErr != errors.Is(err, ErrSchemaMismatch) {
+				t.Errorf("Validate() = %v, want schema mismatch %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package network
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// Sitemap is either a sitemap index, listing child sitemaps, or a URL set.
+type Sitemap struct {
+	XMLName  xml.Name          `xml:""`
+	Sitemaps []SitemapLocation `xml:"sitemap"`
+	URLs     []SitemapLocation `xml:"url"`
+}
+
+type SitemapLocation struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+func (s *Sitemap) IsIndex() bool {
+	return s.XMLName.Local == "sitemapindex"
+}
+
+func (s *Sitemap) Validate() error {
+	switch s.XMLName.Local {
+	case "sitemapindex":
+		for i, sitemap := range s.Sitemaps {
+			if sitemap.Loc == "" {
+				return missingField("sitemapindex.sitemap[%d].loc", i)
+			}
+		}
+	case "urlset":
+		for i, location := range s.URLs {
+			if location.Loc == "" {
+				return missingField("urlset.url[%d].loc", i)
+			}
+		}
+	default:
+		return &SchemaMismatchError{Path: fmt.Sprintf("sitemapindex|urlset (got %q)", s.XMLName.Local)}
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package model
+
+import "runtime/debug"
+
+// Version is set at build time with
+// -ldflags "-X dniprom-cli/internal/model.Version=<version>".
+var Version = ""
+
+// ToolVersion returns Version, falling back to the VCS revision embedded by
+// the Go toolchain.
+func ToolVersion() string {
+	if Version != "" {
+		return Version
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "dev"
+	}
+	if info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" {
+			return setting.Value
+		}
+	}
+	return "dev"
+}
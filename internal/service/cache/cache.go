@@ -0,0 +1,194 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"dniprom-cli/internal/container"
+	"dniprom-cli/pkg/fsx"
+	"dniprom-cli/pkg/logger"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type Cache interface {
+	Get(endpoint string, key string, value any) (bool, error)
+	Put(endpoint string, key string, ttl time.Duration, value any) error
+	Clear() error
+	Stats() ([]EndpointStats, error)
+}
+
+type EndpointStats struct {
+	Endpoint string
+	Entries  int
+	Expired  int
+	Bytes    int64
+}
+
+type entry struct {
+	Endpoint  string          `json:"endpoint"`
+	Key       string          `json:"key"`
+	StoredAt  time.Time       `json:"stored_at"`
+	ExpiresAt time.Time       `json:"expires_at"`
+	Value     json.RawMessage `json:"value"`
+}
+
+type cache struct {
+	container container.Container
+	dir       string
+	// endpoints are the endpoints cached in dir. Clear and Stats only look
+	// into their directories, dir may hold other files.
+	endpoints []string
+}
+
+func NewCache(container container.Container, endpoints ...string) Cache {
+	return &cache{
+		container: container,
+		dir:       container.GetConfig().Cache.Dir,
+		endpoints: endpoints,
+	}
+}
+
+func (c *cache) Get(endpoint string, key string, value any) (bool, error) {
+	log := c.container.GetLogger()
+	data, err := os.ReadFile(c.entryPath(endpoint, key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	} else if err != nil {
+		log.Error("fail to read cache entry", logger.F("key", key), logger.FError(err))
+		return false, err
+	}
+
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		log.Warn("corrupted cache entry", logger.F("key", key), logger.FError(err))
+		return false, nil
+	}
+	if time.Now().After(e.ExpiresAt) {
+		return false, nil
+	}
+	if err := json.Unmarshal(e.Value, value); err != nil {
+		log.Warn("fail to decode cached value", logger.F("key", key), logger.FError(err))
+		return false, nil
+	}
+	return true, nil
+}
+
+func (c *cache) Put(endpoint string, key string, ttl time.Duration, value any) error {
+	log := c.container.GetLogger()
+	raw, err := json.Marshal(value)
+	if err != nil {
+		log.Error("fail to encode cache value", logger.F("key", key), logger.FError(err))
+		return err
+	}
+	now := time.Now()
+	data, err := json.Marshal(entry{
+		Endpoint:  endpoint,
+		Key:       key,
+		StoredAt:  now,
+		ExpiresAt: now.Add(ttl),
+		Value:     raw,
+	})
+	if err != nil {
+		log.Error("fail to encode cache entry", logger.F("key", key), logger.FError(err))
+		return err
+	}
+
+	path := c.entryPath(endpoint, key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		log.Error("fail to create cache directory", logger.FError(err))
+		return err
+	}
+	if err := fsx.WriteFileAtomic(path, data, 0o644); err != nil {
+		log.Error("fail to write cache entry", logger.FError(err))
+		return err
+	}
+	return nil
+}
+
+// Clear removes the cache entries, including the temporary files of
+// interrupted writes. Anything else in the cache directory is left alone.
+func (c *cache) Clear() error {
+	for _, endpoint := range c.endpoints {
+		dir := filepath.Join(c.dir, endpointDirName(endpoint))
+		files, err := os.ReadDir(dir)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		for _, file := range files {
+			if file.IsDir() || !isEntryFile(file.Name()) {
+				continue
+			}
+			if err := os.Remove(filepath.Join(dir, file.Name())); err != nil {
+				return err
+			}
+		}
+		// The directory is only removed once empty.
+		_ = os.Remove(dir)
+	}
+	return nil
+}
+
+func (c *cache) Stats() ([]EndpointStats, error) {
+	now := time.Now()
+	var stats []EndpointStats
+	for _, endpoint := range c.endpoints {
+		dir := endpointDirName(endpoint)
+		files, err := os.ReadDir(filepath.Join(c.dir, dir))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		endpointStats := EndpointStats{Endpoint: dir}
+		for _, file := range files {
+			if filepath.Ext(file.Name()) != ".json" {
+				continue
+			}
+			path := filepath.Join(c.dir, dir, file.Name())
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			endpointStats.Entries++
+			endpointStats.Bytes += int64(len(data))
+			var e entry
+			if err := json.Unmarshal(data, &e); err != nil || now.After(e.ExpiresAt) {
+				endpointStats.Expired++
+			}
+		}
+		stats = append(stats, endpointStats)
+	}
+	return stats, nil
+}
+
+func (c *cache) entryPath(endpoint string, key string) string {
+	sum := sha256.Sum256([]byte(endpoint + "?" + key))
+	return filepath.Join(c.dir, endpointDirName(endpoint), hex.EncodeToString(sum[:])+".json")
+}
+
+// isEntryFile reports whether name is a cache entry or the temporary file of an
+// entry being written.
+func isEntryFile(name string) bool {
+	ext := filepath.Ext(name)
+	return ext == ".json" || ext == ".tmp"
+}
+
+func endpointDirName(endpoint string) string {
+	name := make([]rune, 0, len(endpoint))
+	for _, r := range endpoint {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			name = append(name, r)
+		default:
+			name = append(name, '_')
+		}
+	}
+	return strings.Trim(string(name), "_")
+}
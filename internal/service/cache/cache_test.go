@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"dniprom-cli/internal/container"
+	"dniprom-cli/internal/model"
+	"dniprom-cli/pkg/logger"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testEndpoint = "search/drop-down/"
+
+func TestCacheClearKeepsForeignFiles(t *testing.T) {
+	dir := t.TempDir()
+	config := &model.Config{Cache: model.CacheConfig{Dir: dir}}
+	c := NewCache(container.NewContainer(logger.NewNopLogger(), config), testEndpoint)
+	if err := c.Put(testEndpoint, "q=8617001", time.Hour, "value"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	endpointDir := filepath.Join(dir, endpointDirName(testEndpoint))
+	foreign := []string{
+		filepath.Join(dir, "snapshots.db"),
+		filepath.Join(dir, "other", "entry.json"),
+		filepath.Join(endpointDir, "notes.txt"),
+	}
+	for _, path := range foreign {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("keep"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	leftover := filepath.Join(endpointDir, "entry-123.tmp")
+	if err := os.WriteFile(leftover, []byte("partial"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Clear(); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	var value string
+	if ok, err := c.Get(testEndpoint, "q=8617001", &value); err != nil || ok {
+		t.Errorf("Get after Clear = %v, %v, want a miss", ok, err)
+	}
+	if _, err := os.Stat(leftover); !os.IsNotExist(err) {
+		t.Errorf("temporary entry %s was kept", leftover)
+	}
+	for _, path := range foreign {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("%s was removed: %v", path, err)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package recorder
+
+import (
+	"dniprom-cli/internal/container"
+	"dniprom-cli/pkg/logger"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"unicode/utf8"
+)
+
+const utf8BOM = "\ufeff"
+
+// csvRecorder writes rows into a CSV file. Styling is dropped and the target
+// of every hyperlink is written into an extra column right after its cell.
+// The first row is treated as the header. Footer rows are dropped, so that
+// every row of the file has the columns of the header.
+type csvRecorder struct {
+	container container.Container
+	file      *os.File
+	writer    *csv.Writer
+	rows      int
+	footer    bool
+}
+
+func NewCSVRecorder(container container.Container, path string) (Recorder, error) {
+	log := container.GetLogger()
+	config := container.GetConfig().CSV
+	delimiter, size := utf8.DecodeRuneInString(config.Delimiter)
+	if size == 0 || size != len(config.Delimiter) {
+		return nil, fmt.Errorf("csv delimiter must be a single character, got %q", config.Delimiter)
+	}
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			log.Error("fail to create output directory", logger.FError(err))
+			return nil, err
+		}
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		log.Error("fail to create csv file", logger.F("path", path), logger.FError(err))
+		return nil, err
+	}
+	if config.BOM {
+		if _, err := file.WriteString(utf8BOM); err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+	}
+	writer := csv.NewWriter(file)
+	writer.Comma = delimiter
+	return &csvRecorder{
+		container: container,
+		file:      file,
+		writer:    writer,
+	}, nil
+}
+
+func (r *csvRecorder) PutRich(columns []RichText) error {
+	log := r.container.GetLogger()
+	if r.footer {
+		return nil
+	}
+	record := make([]string, 0, len(columns))
+	for _, column := range columns {
+		value := column.Value
+		if column.Number != nil {
+			value = strconv.FormatFloat(*column.Number, 'f', -1, 64)
+		}
+		record = append(record, value)
+		if column.Link == "" && !column.LinkColumn {
+			continue
+		}
+		if r.rows == 0 {
+			record = append(record, column.Value+" Link")
+		} else {
+			record = append(record, column.Link)
+		}
+	}
+	r.rows++
+	if err := r.writer.Write(record); err != nil {
+		log.Error("fail to write csv row", logger.F("path", r.file.Name()), logger.FError(err))
+		return err
+	}
+	// Flushing every row keeps the file usable when the run is interrupted.
+	r.writer.Flush()
+	return r.writer.Error()
+}
+
+func (r *csvRecorder) StartFooter() {
+	r.footer = true
+}
+
+func (r *csvRecorder) Close() error {
+	r.writer.Flush()
+	if err := r.writer.Error(); err != nil {
+		_ = r.file.Close()
+		return err
+	}
+	return r.file.Close()
+}
@@ -0,0 +1,56 @@
+package recorder
+
+import (
+	"dniprom-cli/internal/container"
+	"dniprom-cli/internal/model"
+	"dniprom-cli/pkg/logger"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Every row of the file has the width of the header, the footer is dropped.
+func TestCSVRecorderDropsFooter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "warranty.csv")
+	config := &model.Config{CSV: model.CSVConfig{Delimiter: ","}}
+	rec, err := NewCSVRecorder(container.NewContainer(logger.NewNopLogger(), config), path)
+	if err != nil {
+		t.Fatalf("NewCSVRecorder: %v", err)
+	}
+	rows := [][]RichText{
+		{{Value: "Product Code"}, {Value: "Title"}, {Value: "URL", LinkColumn: true}},
+		{{Value: "8617001"}, {Value: "Дриль"}, {Value: "page", Link: "https://dnipro-m.ua/ua/p/8617001/"}},
+	}
+	for _, row := range rows {
+		if err := rec.PutRich(row); err != nil {
+			t.Fatalf("PutRich: %v", err)
+		}
+	}
+	rec.(FooterRecorder).StartFooter()
+	for _, row := range [][]RichText{
+		{{Value: "Start at: "}, {Value: "2026-10-18 09:00:00"}},
+		{{Value: "Powered by"}, {Value: "iOSmates", Link: "https://iosmates.com"}},
+	} {
+		if err := rec.PutRich(row); err != nil {
+			t.Fatalf("PutRich: %v", err)
+		}
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	// encoding/csv rejects rows whose width differs from the first one.
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if len(records) != len(rows) {
+		t.Errorf("file has %d rows, want %d: %q", len(records), len(rows), records)
+	}
+}
@@ -11,6 +11,13 @@ import (
 
 type Recorder interface {
 	PutRich(columns []RichText) error
+	Close() error
+}
+
+// FooterRecorder is implemented by recorders that tell the footer rows apart
+// from the data rows. Rows put after StartFooter belong to the footer.
+type FooterRecorder interface {
+	StartFooter()
 }
 
 type recorder struct {
@@ -57,6 +64,21 @@ func (r *recorder) PutRich(columns []RichText) error {
 				},
 			},
 		}
+		if column.Number != nil {
+			cell.UserEnteredValue = &sheets.ExtendedValue{
+				NumberValue: column.Number,
+			}
+			cell.UserEnteredFormat.TextFormat = &sheets.TextFormat{
+				Bold: column.IsBold,
+			}
+			if column.NumberFormat != "" {
+				cell.UserEnteredFormat.NumberFormat = &sheets.NumberFormat{
+					Type:    "NUMBER",
+					Pattern: column.NumberFormat,
+				}
+			}
+			cell.TextFormatRuns = nil
+		}
 		if column.Link != "" {
 			formulaLink := fmt.Sprintf(`=HYPERLINK("%s","%s")`, column.Link, column.Value)
 			cell.UserEnteredValue = &sheets.ExtendedValue{
@@ -97,6 +119,10 @@ func (r *recorder) PutRich(columns []RichText) error {
 	return nil
 }
 
+func (r *recorder) Close() error {
+	return nil
+}
+
 func convertToSpreadsheetColor(color *Color) *sheets.Color {
 	if color == nil {
 		return nil
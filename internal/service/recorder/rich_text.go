@@ -1,8 +1,16 @@
 package recorder
 
 type RichText struct {
-	Value           string
-	Link            string
+	Value string
+	// Number, when set, is recorded as a numeric cell instead of Value.
+	Number *float64
+	// NumberFormat is the spreadsheet pattern of Number, e.g. "0.00". Empty
+	// keeps the default format.
+	NumberFormat string
+	Link         string
+	// LinkColumn marks cells of a hyperlink column, so that recorders keeping
+	// the link target in a separate column do so even when Link is empty.
+	LinkColumn      bool
 	IsBold          bool
 	BackgroundColor *Color
 }
@@ -0,0 +1,181 @@
+package recorder
+
+import (
+	"dniprom-cli/internal/container"
+	"dniprom-cli/pkg/logger"
+	"fmt"
+	"github.com/xuri/excelize/v2"
+	"math"
+	"os"
+	"path/filepath"
+)
+
+const xlsxSheetName = "Warranty"
+
+type xlsxStyleKey struct {
+	bold            bool
+	link            bool
+	backgroundColor string
+	numberFormat    string
+}
+
+// xlsxRecorder writes rows into a local workbook, saved when the recorder is
+// closed. The first row is treated as the header: it is frozen and carries the
+// autofilter, which spans the data rows up to the footer.
+type xlsxRecorder struct {
+	container container.Container
+	path      string
+	file      *excelize.File
+	styles    map[xlsxStyleKey]int
+	row       int
+	columns   int
+	lastRow   int
+	footer    bool
+}
+
+func NewXLSXRecorder(container container.Container, path string) (Recorder, error) {
+	log := container.GetLogger()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		log.Error("fail to create output directory", logger.FError(err))
+		return nil, err
+	}
+	file := excelize.NewFile()
+	if err := file.SetSheetName(file.GetSheetName(0), xlsxSheetName); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return &xlsxRecorder{
+		container: container,
+		path:      path,
+		file:      file,
+		styles:    make(map[xlsxStyleKey]int),
+	}, nil
+}
+
+func (r *xlsxRecorder) PutRich(columns []RichText) error {
+	log := r.container.GetLogger()
+	r.row++
+	for i, column := range columns {
+		cell, err := excelize.CoordinatesToCellName(i+1, r.row)
+		if err != nil {
+			return err
+		}
+		if err := r.setCell(cell, column); err != nil {
+			log.Error("fail to write xlsx cell", logger.F("cell", cell), logger.FError(err))
+			return err
+		}
+	}
+	if r.row == 1 {
+		r.columns = len(columns)
+		return r.file.SetPanes(xlsxSheetName, &excelize.Panes{
+			Freeze:      true,
+			YSplit:      1,
+			TopLeftCell: "A2",
+			ActivePane:  "bottomLeft",
+		})
+	}
+	if !r.footer {
+		r.lastRow = r.row
+	}
+	return nil
+}
+
+func (r *xlsxRecorder) StartFooter() {
+	r.footer = true
+}
+
+func (r *xlsxRecorder) setCell(cell string, column RichText) error {
+	var err error
+	switch {
+	case column.Number != nil:
+		err = r.file.SetCellFloat(xlsxSheetName, cell, *column.Number, -1, 64)
+	default:
+		err = r.file.SetCellStr(xlsxSheetName, cell, column.Value)
+	}
+	if err != nil {
+		return err
+	}
+	if column.Link != "" {
+		if err := r.file.SetCellHyperLink(xlsxSheetName, cell, column.Link, "External"); err != nil {
+			return err
+		}
+	}
+	styleID, err := r.style(column)
+	if err != nil || styleID == 0 {
+		return err
+	}
+	return r.file.SetCellStyle(xlsxSheetName, cell, cell, styleID)
+}
+
+// style returns the ID of the cell style matching column, creating it on first
+// use. Zero is the default style.
+func (r *xlsxRecorder) style(column RichText) (int, error) {
+	key := xlsxStyleKey{
+		bold: column.IsBold,
+		link: column.Link != "",
+	}
+	if column.BackgroundColor != nil {
+		key.backgroundColor = hexColor(column.BackgroundColor)
+	}
+	if column.Number != nil {
+		key.numberFormat = column.NumberFormat
+	}
+	if key == (xlsxStyleKey{}) {
+		return 0, nil
+	}
+	if styleID, ok := r.styles[key]; ok {
+		return styleID, nil
+	}
+
+	style := &excelize.Style{}
+	if key.bold || key.link {
+		style.Font = &excelize.Font{Bold: key.bold}
+		if key.link {
+			style.Font.Color = "0563C1"
+			style.Font.Underline = "single"
+		}
+	}
+	if key.backgroundColor != "" {
+		style.Fill = excelize.Fill{
+			Type:    "pattern",
+			Pattern: 1,
+			Color:   []string{key.backgroundColor},
+		}
+	}
+	if key.numberFormat != "" {
+		style.CustomNumFmt = &key.numberFormat
+	}
+	styleID, err := r.file.NewStyle(style)
+	if err != nil {
+		return 0, err
+	}
+	r.styles[key] = styleID
+	return styleID, nil
+}
+
+func (r *xlsxRecorder) Close() error {
+	log := r.container.GetLogger()
+	defer r.file.Close()
+	if r.columns > 0 {
+		lastCell, err := excelize.CoordinatesToCellName(r.columns, max(r.lastRow, 1))
+		if err != nil {
+			return err
+		}
+		if err := r.file.AutoFilter(xlsxSheetName, "A1:"+lastCell, nil); err != nil {
+			log.Error("fail to set xlsx autofilter", logger.FError(err))
+			return err
+		}
+	}
+	if err := r.file.SaveAs(r.path); err != nil {
+		log.Error("fail to save xlsx file", logger.F("path", r.path), logger.FError(err))
+		return err
+	}
+	return nil
+}
+
+func hexColor(color *Color) string {
+	channel := func(value float64) int {
+		return int(math.Round(math.Max(0, math.Min(1, value)) * 255))
+	}
+	return fmt.Sprintf("%02X%02X%02X", channel(color.Red), channel(color.Green), channel(color.Blue))
+}
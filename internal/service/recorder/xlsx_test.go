@@ -0,0 +1,65 @@
+package recorder
+
+import (
+	"dniprom-cli/internal/container"
+	"dniprom-cli/internal/model"
+	"dniprom-cli/pkg/logger"
+	"github.com/xuri/excelize/v2"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestXLSXRecorderAutoFilterStopsAtFooter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "warranty.xlsx")
+	rec, err := NewXLSXRecorder(container.NewContainer(logger.NewNopLogger(), &model.Config{}), path)
+	if err != nil {
+		t.Fatalf("NewXLSXRecorder: %v", err)
+	}
+	price := 1234.5
+	rows := [][]RichText{
+		{{Value: "Product Code"}, {Value: "New Price"}},
+		{{Value: "8617001"}, {Value: "1234.50", Number: &price, NumberFormat: "0.00"}},
+		{{Value: "8617002"}, {Value: "unknown"}},
+	}
+	for _, row := range rows {
+		if err := rec.PutRich(row); err != nil {
+			t.Fatalf("PutRich: %v", err)
+		}
+	}
+	// The footer rows are as wide as the header.
+	rec.(FooterRecorder).StartFooter()
+	for _, row := range [][]RichText{
+		{{Value: "Start at: "}, {Value: "2026-10-18 09:00:00"}},
+		{{Value: "End at: "}, {Value: "2026-10-18 09:01:00"}},
+	} {
+		if err := rec.PutRich(row); err != nil {
+			t.Fatalf("PutRich: %v", err)
+		}
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	file, err := excelize.OpenFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	var filterRange string
+	for _, name := range file.GetDefinedName() {
+		if name.Name == "_xlnm._FilterDatabase" {
+			filterRange = name.RefersTo
+		}
+	}
+	if !strings.HasSuffix(filterRange, "$A$1:$B$3") {
+		t.Errorf("autofilter range = %q, want A1:B3", filterRange)
+	}
+	value, err := file.GetCellValue(xlsxSheetName, "B2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != "1234.50" {
+		t.Errorf("B2 = %q, want %q", value, "1234.50")
+	}
+}
@@ -0,0 +1,152 @@
+package snapshot
+
+import "dniprom-cli/internal/model/app"
+
+// Product is the stored state of a product. It mirrors app.ProductWarranty
+// with explicit field names, so that changes to the application model don't
+// silently change what is written to, or read back from, the database.
+type Product struct {
+	ID               int64             `json:"id"`
+	Title            string            `json:"title"`
+	Names            Names             `json:"names"`
+	Status           app.ProductStatus `json:"status"`
+	WarrantyText     string            `json:"warranty_text"`
+	Warranties       []Warranty        `json:"warranties,omitempty"`
+	WarrantyDuration Duration          `json:"warranty_duration"`
+	ServiceNotes     string            `json:"service_notes,omitempty"`
+	OldPrice         string            `json:"old_price"`
+	NewPrice         string            `json:"new_price"`
+	// OldPriceAmount and NewPriceAmount are null when the price is unknown.
+	OldPriceAmount *float64 `json:"old_price_amount"`
+	NewPriceAmount *float64 `json:"new_price_amount"`
+	Details        Details  `json:"details"`
+}
+
+type Names struct {
+	UK string `json:"uk,omitempty"`
+	RU string `json:"ru,omitempty"`
+	EN string `json:"en,omitempty"`
+}
+
+type Warranty struct {
+	Component    string   `json:"component,omitempty"`
+	Term         string   `json:"term"`
+	Duration     Duration `json:"duration"`
+	Conditions   string   `json:"conditions,omitempty"`
+	ServiceNotes string   `json:"service_notes,omitempty"`
+}
+
+type Duration struct {
+	BaseMonths     int    `json:"base_months"`
+	ExtendedMonths int    `json:"extended_months"`
+	Conditions     string `json:"conditions,omitempty"`
+	Parsed         bool   `json:"parsed"`
+}
+
+type Details struct {
+	CategoryPath []string `json:"category_path,omitempty"`
+	Brand        string   `json:"brand,omitempty"`
+	URL          string   `json:"url,omitempty"`
+	ImageURL     string   `json:"image_url,omitempty"`
+	Availability string   `json:"availability,omitempty"`
+	Rating       *float64 `json:"rating,omitempty"`
+}
+
+func NewProduct(productWarranty *app.ProductWarranty) Product {
+	warranties := make([]Warranty, 0, len(productWarranty.Warranties))
+	for _, warranty := range productWarranty.Warranties {
+		warranties = append(warranties, Warranty{
+			Component:    warranty.Component,
+			Term:         warranty.Term,
+			Duration:     newDuration(warranty.Duration),
+			Conditions:   warranty.Conditions,
+			ServiceNotes: warranty.ServiceNotes,
+		})
+	}
+	details := productWarranty.Details
+	return Product{
+		ID:    productWarranty.ID,
+		Title: productWarranty.Title,
+		Names: Names{
+			UK: productWarranty.Names.UK,
+			RU: productWarranty.Names.RU,
+			EN: productWarranty.Names.EN,
+		},
+		Status:           productWarranty.Status,
+		WarrantyText:     productWarranty.WarrantyText,
+		Warranties:       warranties,
+		WarrantyDuration: newDuration(productWarranty.WarrantyDuration),
+		ServiceNotes:     productWarranty.ServiceNotes,
+		OldPrice:         productWarranty.OldPrice,
+		NewPrice:         productWarranty.NewPrice,
+		OldPriceAmount:   productWarranty.OldPriceAmount,
+		NewPriceAmount:   productWarranty.NewPriceAmount,
+		Details: Details{
+			CategoryPath: details.CategoryPath,
+			Brand:        details.Brand,
+			URL:          details.URL,
+			ImageURL:     details.ImageURL,
+			Availability: details.Availability,
+			Rating:       details.Rating,
+		},
+	}
+}
+
+// ProductWarranty restores the application model of the stored product.
+func (p Product) ProductWarranty(code string) app.ProductWarranty {
+	warranties := make([]app.WarrantyEntry, 0, len(p.Warranties))
+	for _, warranty := range p.Warranties {
+		warranties = append(warranties, app.WarrantyEntry{
+			Component:    warranty.Component,
+			Term:         warranty.Term,
+			Duration:     warranty.Duration.warrantyDuration(),
+			Conditions:   warranty.Conditions,
+			ServiceNotes: warranty.ServiceNotes,
+		})
+	}
+	return app.ProductWarranty{
+		ID:    p.ID,
+		Code:  code,
+		Title: p.Title,
+		Names: app.LocalizedNames{
+			UK: p.Names.UK,
+			RU: p.Names.RU,
+			EN: p.Names.EN,
+		},
+		WarrantyText:     p.WarrantyText,
+		Warranties:       warranties,
+		WarrantyDuration: p.WarrantyDuration.warrantyDuration(),
+		ServiceNotes:     p.ServiceNotes,
+		OldPrice:         p.OldPrice,
+		NewPrice:         p.NewPrice,
+		OldPriceAmount:   p.OldPriceAmount,
+		NewPriceAmount:   p.NewPriceAmount,
+		Details: app.ProductDetails{
+			CategoryPath: p.Details.CategoryPath,
+			Brand:        p.Details.Brand,
+			URL:          p.Details.URL,
+			ImageURL:     p.Details.ImageURL,
+			Availability: p.Details.Availability,
+			Rating:       p.Details.Rating,
+		},
+		Status: p.Status,
+	}
+}
+
+func newDuration(duration app.WarrantyDuration) Duration {
+	return Duration{
+		BaseMonths:     duration.BaseMonths,
+		ExtendedMonths: duration.ExtendedMonths,
+		Conditions:     duration.Conditions,
+		Parsed:         duration.Parsed,
+	}
+}
+
+func (d Duration) warrantyDuration() app.WarrantyDuration {
+	return app.WarrantyDuration{
+		BaseMonths:     d.BaseMonths,
+		ExtendedMonths: d.ExtendedMonths,
+		Conditions:     d.Conditions,
+		Parsed:         d.Parsed,
+	}
+}
@@ -0,0 +1,288 @@
+package snapshot
+
+import (
+	"dniprom-cli/internal/container"
+	"dniprom-cli/pkg/logger"
+	"encoding/json"
+	"errors"
+	"go.etcd.io/bbolt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// runIDLayout keeps run IDs sortable in the order the runs were started.
+const runIDLayout = "20060102T150405.000000000Z"
+
+var (
+	runsBucket      = []byte("runs")
+	snapshotsBucket = []byte("snapshots")
+)
+
+var ErrRunNotFound = errors.New("run not found")
+
+type Store interface {
+	StartRun(startedAt time.Time) (Run, error)
+	// Put stores the snapshots in a single transaction.
+	Put(snapshots ...Snapshot) error
+	// FinishRun records the end of a run. An interrupted run didn't go through
+	// every product.
+	FinishRun(runID string, finishedAt time.Time, interrupted bool) error
+	Runs() ([]Run, error)
+	RunSnapshots(runID string) ([]Snapshot, error)
+	History(code string) ([]Snapshot, error)
+	Close() error
+}
+
+type Run struct {
+	ID         string    `json:"id"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Products   int       `json:"products"`
+	// Interrupted is set when the run was stopped before going through every
+	// product.
+	Interrupted bool `json:"interrupted,omitempty"`
+}
+
+// Snapshot is the result collected for a single product code during a run.
+type Snapshot struct {
+	RunID      string    `json:"run_id"`
+	Code       string    `json:"code"`
+	Index      int       `json:"index"`
+	RecordedAt time.Time `json:"recorded_at"`
+	// Error is the message of the error that ended the fetch, if any.
+	Error   string  `json:"error,omitempty"`
+	Product Product `json:"product"`
+}
+
+// lockTimeout bounds the wait for the database lock held by another process.
+const lockTimeout = 5 * time.Second
+
+// store opens the database for every transaction only, so that a long
+// warranty run doesn't lock the history command out of the database.
+type store struct {
+	container container.Container
+	path      string
+	readOnly  bool
+}
+
+func NewStore(container container.Container) (Store, error) {
+	log := container.GetLogger()
+	path := container.GetConfig().Snapshot.Path
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		log.Error("fail to create snapshot directory", logger.FError(err))
+		return nil, err
+	}
+	s := &store{
+		container: container,
+		path:      path,
+	}
+	err := s.update(func(tx *bbolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(runsBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(snapshotsBucket)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// NewReadOnlyStore opens an existing database for reading. It neither creates
+// the database nor waits for a warranty run writing into it to end.
+func NewReadOnlyStore(container container.Container) (Store, error) {
+	log := container.GetLogger()
+	path := container.GetConfig().Snapshot.Path
+	if _, err := os.Stat(path); err != nil {
+		log.Error("fail to open snapshot database", logger.F("path", path), logger.FError(err))
+		return nil, err
+	}
+	return &store{
+		container: container,
+		path:      path,
+		readOnly:  true,
+	}, nil
+}
+
+func (s *store) open() (*bbolt.DB, error) {
+	db, err := bbolt.Open(s.path, 0o644, &bbolt.Options{
+		Timeout:  lockTimeout,
+		ReadOnly: s.readOnly,
+	})
+	if err != nil {
+		s.container.GetLogger().Error(
+			"fail to open snapshot database",
+			logger.F("path", s.path),
+			logger.FError(err),
+		)
+		return nil, err
+	}
+	return db, nil
+}
+
+func (s *store) update(fn func(tx *bbolt.Tx) error) error {
+	db, err := s.open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return db.Update(fn)
+}
+
+// view runs fn in a read transaction. A database without buckets yet, only
+// possible in read-only mode, reads as empty.
+func (s *store) view(fn func(tx *bbolt.Tx) error) error {
+	db, err := s.open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return db.View(func(tx *bbolt.Tx) error {
+		if tx.Bucket(runsBucket) == nil || tx.Bucket(snapshotsBucket) == nil {
+			return nil
+		}
+		return fn(tx)
+	})
+}
+
+// NewRunID returns the ID of a run started at startedAt.
+func NewRunID(startedAt time.Time) string {
+	return startedAt.UTC().Format(runIDLayout)
+}
+
+func (s *store) StartRun(startedAt time.Time) (Run, error) {
+	run := Run{
+		ID:        NewRunID(startedAt),
+		StartedAt: startedAt.UTC(),
+	}
+	err := s.update(func(tx *bbolt.Tx) error {
+		if _, err := tx.Bucket(snapshotsBucket).CreateBucketIfNotExists([]byte(run.ID)); err != nil {
+			return err
+		}
+		return putJSON(tx.Bucket(runsBucket), run.ID, run)
+	})
+	return run, err
+}
+
+func (s *store) Put(snapshots ...Snapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+	recordedAt := time.Now().UTC()
+	return s.update(func(tx *bbolt.Tx) error {
+		for _, snapshot := range snapshots {
+			if snapshot.RecordedAt.IsZero() {
+				snapshot.RecordedAt = recordedAt
+			}
+			bucket := tx.Bucket(snapshotsBucket).Bucket([]byte(snapshot.RunID))
+			if bucket == nil {
+				return ErrRunNotFound
+			}
+			if err := putJSON(bucket, snapshot.Code, snapshot); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *store) FinishRun(runID string, finishedAt time.Time, interrupted bool) error {
+	return s.update(func(tx *bbolt.Tx) error {
+		var run Run
+		found, err := getJSON(tx.Bucket(runsBucket), runID, &run)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return ErrRunNotFound
+		}
+		run.FinishedAt = finishedAt.UTC()
+		run.Interrupted = interrupted
+		if bucket := tx.Bucket(snapshotsBucket).Bucket([]byte(runID)); bucket != nil {
+			run.Products = bucket.Stats().KeyN
+		}
+		return putJSON(tx.Bucket(runsBucket), runID, run)
+	})
+}
+
+// Runs returns every run, oldest first.
+func (s *store) Runs() ([]Run, error) {
+	var runs []Run
+	err := s.view(func(tx *bbolt.Tx) error {
+		return tx.Bucket(runsBucket).ForEach(func(_, value []byte) error {
+			var run Run
+			if err := json.Unmarshal(value, &run); err != nil {
+				return err
+			}
+			runs = append(runs, run)
+			return nil
+		})
+	})
+	return runs, err
+}
+
+// RunSnapshots returns the snapshots of a run in the order the products were
+// requested.
+func (s *store) RunSnapshots(runID string) ([]Snapshot, error) {
+	var snapshots []Snapshot
+	err := s.view(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(snapshotsBucket).Bucket([]byte(runID))
+		if bucket == nil {
+			return ErrRunNotFound
+		}
+		return bucket.ForEach(func(_, value []byte) error {
+			var snapshot Snapshot
+			if err := json.Unmarshal(value, &snapshot); err != nil {
+				return err
+			}
+			snapshots = append(snapshots, snapshot)
+			return nil
+		})
+	})
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].Index < snapshots[j].Index
+	})
+	return snapshots, err
+}
+
+// History returns every snapshot of a product code, oldest first.
+func (s *store) History(code string) ([]Snapshot, error) {
+	var snapshots []Snapshot
+	err := s.view(func(tx *bbolt.Tx) error {
+		runs := tx.Bucket(snapshotsBucket)
+		return runs.ForEachBucket(func(runID []byte) error {
+			var snapshot Snapshot
+			found, err := getJSON(runs.Bucket(runID), code, &snapshot)
+			if err != nil || !found {
+				return err
+			}
+			snapshots = append(snapshots, snapshot)
+			return nil
+		})
+	})
+	return snapshots, err
+}
+
+// Close is a no-op, the database is closed after every transaction.
+func (s *store) Close() error {
+	return nil
+}
+
+func putJSON(bucket *bbolt.Bucket, key string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return bucket.Put([]byte(key), data)
+}
+
+func getJSON(bucket *bbolt.Bucket, key string, value any) (bool, error) {
+	data := bucket.Get([]byte(key))
+	if data == nil {
+		return false, nil
+	}
+	return true, json.Unmarshal(data, value)
+}
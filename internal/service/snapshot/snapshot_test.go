@@ -0,0 +1,194 @@
+package snapshot
+
+import (
+	"dniprom-cli/internal/container"
+	"dniprom-cli/internal/model"
+	"dniprom-cli/internal/model/app"
+	"dniprom-cli/pkg/logger"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newTestContainer(path string) container.Container {
+	config := &model.Config{
+		Snapshot: model.SnapshotConfig{
+			Enabled: true,
+			Path:    path,
+		},
+	}
+	return container.NewContainer(logger.NewNopLogger(), config)
+}
+
+func newTestStore(t *testing.T) Store {
+	t.Helper()
+	store, err := NewStore(newTestContainer(filepath.Join(t.TempDir(), "snapshots.db")))
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = store.Close()
+	})
+	return store
+}
+
+func TestStoreRun(t *testing.T) {
+	store := newTestStore(t)
+	startedAt := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	run, err := store.StartRun(startedAt)
+	if err != nil {
+		t.Fatalf("StartRun: %v", err)
+	}
+
+	price, oldPrice, rating := 2899.0, 3299.0, 4.8
+	productWarranty := app.ProductWarranty{
+		ID:    101,
+		Code:  "8617001",
+		Title: "Дриль-шуруповерт CD-200BC",
+		Names: app.LocalizedNames{UK: "Дриль-шуруповерт CD-200BC"},
+		Warranties: []app.WarrantyEntry{
+			{
+				Component: "Інструмент",
+				Term:      "36 місяців",
+				Duration:  app.WarrantyDuration{BaseMonths: 36, Parsed: true},
+			},
+		},
+		WarrantyText:     "Інструмент: 36 місяців",
+		WarrantyDuration: app.WarrantyDuration{BaseMonths: 36, Parsed: true},
+		OldPrice:         "3299.00",
+		NewPrice:         "2899.00",
+		OldPriceAmount:   &oldPrice,
+		NewPriceAmount:   &price,
+		Details:          app.ProductDetails{Brand: "Dnipro-M", Rating: &rating},
+		Status:           app.ProductStatusOK,
+	}
+	notFound := app.ProductWarranty{ID: -1, Code: "1000000", Status: app.ProductStatusNotFound}
+	err = store.Put(
+		Snapshot{RunID: run.ID, Code: "1000000", Index: 1, Error: "product not found", Product: NewProduct(&notFound)},
+		Snapshot{RunID: run.ID, Code: "8617001", Index: 0, Product: NewProduct(&productWarranty)},
+	)
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := store.FinishRun(run.ID, startedAt.Add(time.Minute), false); err != nil {
+		t.Fatalf("FinishRun: %v", err)
+	}
+
+	runs, err := store.Runs()
+	if err != nil {
+		t.Fatalf("Runs: %v", err)
+	}
+	if len(runs) != 1 || runs[0].Products != 2 || runs[0].FinishedAt.IsZero() {
+		t.Fatalf("Runs = %+v, want one finished run with 2 products", runs)
+	}
+
+	snapshots, err := store.RunSnapshots(run.ID)
+	if err != nil {
+		t.Fatalf("RunSnapshots: %v", err)
+	}
+	if len(snapshots) != 2 || snapshots[0].Code != "8617001" || snapshots[1].Code != "1000000" {
+		t.Fatalf("RunSnapshots = %+v, want products in request order", snapshots)
+	}
+	if snapshots[0].RecordedAt.IsZero() {
+		t.Errorf("RecordedAt is not set")
+	}
+	if got := snapshots[0].Product.ProductWarranty("8617001"); !reflect.DeepEqual(got, productWarranty) {
+		t.Errorf("ProductWarranty() = %+v, want %+v", got, productWarranty)
+	}
+
+	history, err := store.History("8617001")
+	if err != nil {
+		t.Fatalf("History: %v", err)
+	}
+	if len(history) != 1 || history[0].RunID != run.ID {
+		t.Errorf("History = %+v, want the snapshot of run %s", history, run.ID)
+	}
+
+	if err := store.Put(Snapshot{RunID: "missing", Code: "8617001"}); err != ErrRunNotFound {
+		t.Errorf("Put into a missing run = %v, want %v", err, ErrRunNotFound)
+	}
+}
+
+func TestSnapshotJSON(t *testing.T) {
+	price := 2899.0
+	data, err := json.Marshal(Snapshot{
+		RunID: "20261018T090000.000000000Z",
+		Code:  "8617001",
+		Product: NewProduct(&app.ProductWarranty{
+			ID:             101,
+			NewPrice:       "2899.00",
+			NewPriceAmount: &price,
+			Status:         app.ProductStatusOK,
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var record map[string]any
+	if err := json.Unmarshal(data, &record); err != nil {
+		t.Fatal(err)
+	}
+	product, ok := record["product"].(map[string]any)
+	if !ok {
+		t.Fatalf("record %s has no product object", data)
+	}
+	for key, want := range map[string]any{
+		"id":               101.0,
+		"status":           "ok",
+		"new_price":        "2899.00",
+		"new_price_amount": 2899.0,
+		"old_price_amount": nil,
+	} {
+		if got, ok := product[key]; !ok || got != want {
+			t.Errorf("product[%q] = %v, want %v", key, got, want)
+		}
+	}
+}
+
+// A read-only store reads the runs of a store that is still open for writing,
+// and never creates the database.
+func TestReadOnlyStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshots.db")
+	if _, err := NewReadOnlyStore(newTestContainer(path)); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("NewReadOnlyStore on a missing database error = %v, want %v", err, fs.ErrNotExist)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("NewReadOnlyStore created %s", path)
+	}
+
+	writer, err := NewStore(newTestContainer(path))
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	defer writer.Close()
+	run, err := writer.StartRun(time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("StartRun: %v", err)
+	}
+	if err := writer.Put(Snapshot{RunID: run.ID, Code: "8617001"}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	reader, err := NewReadOnlyStore(newTestContainer(path))
+	if err != nil {
+		t.Fatalf("NewReadOnlyStore: %v", err)
+	}
+	defer reader.Close()
+	history, err := reader.History("8617001")
+	if err != nil || len(history) != 1 {
+		t.Fatalf("History = %d snapshots, %v, want 1", len(history), err)
+	}
+	if _, err := reader.StartRun(time.Now()); err == nil {
+		t.Error("StartRun on a read-only store succeeded")
+	}
+
+	// The writer goes on once the reader is done.
+	if err := writer.Put(Snapshot{RunID: run.ID, Code: "8617002"}); err != nil {
+		t.Fatalf("Put after read: %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package worker
+
+import (
+	"dniprom-cli/internal/model/app"
+	"dniprom-cli/internal/service/snapshot"
+	"dniprom-cli/pkg/logger"
+)
+
+// LastRunSnapshots returns the snapshots of the latest run that went through
+// every product, or nil when there is none. Interrupted runs are skipped as
+// they would report the products they missed as removed.
+func (h *History) LastRunSnapshots() ([]snapshot.Snapshot, error) {
+	log := h.container.GetLogger()
+	runs, err := h.store.Runs()
+	if err != nil {
+		log.Error("fail to read snapshot runs", logger.FError(err))
+		return nil, err
+	}
+	for i := len(runs) - 1; i >= 0; i-- {
+		if runs[i].FinishedAt.IsZero() || runs[i].Interrupted {
+			continue
+		}
+		snapshots, err := h.store.RunSnapshots(runs[i].ID)
+		if err != nil {
+			log.Error("fail to read run snapshots", logger.F("runID", runs[i].ID), logger.FError(err))
+			return nil, err
+		}
+		return snapshots, nil
+	}
+	return nil, nil
+}
+
+// DetectChange classifies the current result of a product against its
+// snapshot from the previous run. previous is nil when the product wasn't
+// part of that run. An empty ChangeType means the results can't be compared,
+// e.g. because one of the fetches failed.
+func DetectChange(previous *snapshot.Snapshot, current *app.ProductWarranty) app.ChangeType {
+	if previous == nil {
+		if current.Status == app.ProductStatusOK {
+			return app.ChangeNew
+		}
+		return ""
+	}
+	was := previous.Product
+	switch {
+	case current.Status == app.ProductStatusNotFound && was.Status == app.ProductStatusOK:
+		return app.ChangeRemoved
+	case current.Status == app.ProductStatusNotFound && was.Status == app.ProductStatusNotFound:
+		return app.ChangeUnchanged
+	case current.Status == app.ProductStatusOK && was.Status == app.ProductStatusNotFound:
+		return app.ChangeNew
+	case current.Status != app.ProductStatusOK || was.Status != app.ProductStatusOK:
+		return ""
+	}
+	if was.NewPriceAmount != nil && current.NewPriceAmount != nil {
+		switch {
+		case *current.NewPriceAmount > *was.NewPriceAmount:
+			return app.ChangePriceUp
+		case *current.NewPriceAmount < *was.NewPriceAmount:
+			return app.ChangePriceDown
+		}
+	}
+	if !samePrice(was.NewPriceAmount, current.NewPriceAmount) || !samePrice(was.OldPriceAmount, current.OldPriceAmount) {
+		return app.ChangePrice
+	}
+	if current.WarrantyText != was.WarrantyText {
+		return app.ChangeWarranty
+	}
+	return app.ChangeUnchanged
+}
@@ -0,0 +1,122 @@
+package worker
+
+import (
+	"dniprom-cli/internal/container"
+	"dniprom-cli/internal/model"
+	"dniprom-cli/internal/model/app"
+	"dniprom-cli/internal/service/snapshot"
+	"dniprom-cli/pkg/logger"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func price(value float64) *float64 {
+	return &value
+}
+
+func TestDetectChange(t *testing.T) {
+	ok := func(newPrice, oldPrice *float64, warranty string) app.ProductWarranty {
+		return app.ProductWarranty{
+			Status:         app.ProductStatusOK,
+			NewPriceAmount: newPrice,
+			OldPriceAmount: oldPrice,
+			WarrantyText:   warranty,
+		}
+	}
+	notFound := app.ProductWarranty{Status: app.ProductStatusNotFound}
+	failed := app.ProductWarranty{Status: app.ProductStatusRateLimited}
+
+	tests := []struct {
+		name     string
+		previous *app.ProductWarranty
+		current  app.ProductWarranty
+		want     app.ChangeType
+	}{
+		{name: "new product", current: ok(price(100), nil, "12 місяців"), want: app.ChangeNew},
+		{name: "new but failed", current: failed, want: ""},
+		{name: "unchanged", previous: ptrTo(ok(price(100), nil, "12 місяців")), current: ok(price(100), nil, "12 місяців"), want: app.ChangeUnchanged},
+		{name: "price up", previous: ptrTo(ok(price(100), nil, "12 місяців")), current: ok(price(120), nil, "12 місяців"), want: app.ChangePriceUp},
+		{name: "price down", previous: ptrTo(ok(price(100), nil, "12 місяців")), current: ok(price(90), price(100), "12 місяців"), want: app.ChangePriceDown},
+		{name: "price appears", previous: ptrTo(ok(nil, nil, "12 місяців")), current: ok(price(100), nil, "12 місяців"), want: app.ChangePrice},
+		{name: "price disappears", previous: ptrTo(ok(price(100), nil, "12 місяців")), current: ok(nil, nil, "12 місяців"), want: app.ChangePrice},
+		{name: "old price only", previous: ptrTo(ok(price(100), nil, "12 місяців")), current: ok(price(100), price(120), "12 місяців"), want: app.ChangePrice},
+		{name: "old price changes", previous: ptrTo(ok(price(100), price(110), "12 місяців")), current: ok(price(100), price(120), "12 місяців"), want: app.ChangePrice},
+		{name: "warranty", previous: ptrTo(ok(price(100), nil, "12 місяців")), current: ok(price(100), nil, "24 місяці"), want: app.ChangeWarranty},
+		{name: "removed", previous: ptrTo(ok(price(100), nil, "12 місяців")), current: notFound, want: app.ChangeRemoved},
+		{name: "still not found", previous: &notFound, current: notFound, want: app.ChangeUnchanged},
+		{name: "back in catalog", previous: &notFound, current: ok(price(100), nil, ""), want: app.ChangeNew},
+		{name: "failed now", previous: ptrTo(ok(price(100), nil, "")), current: failed, want: ""},
+		{name: "failed before", previous: &failed, current: ok(price(100), nil, ""), want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var previous *snapshot.Snapshot
+			if tt.previous != nil {
+				previous = &snapshot.Snapshot{Product: snapshot.NewProduct(tt.previous)}
+			}
+			current := tt.current
+			if got := DetectChange(previous, &current); got != tt.want {
+				t.Errorf("DetectChange() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func ptrTo(productWarranty app.ProductWarranty) *app.ProductWarranty {
+	return &productWarranty
+}
+
+func TestLastRunSnapshotsSkipsInterruptedRuns(t *testing.T) {
+	config := &model.Config{
+		Snapshot: model.SnapshotConfig{
+			Enabled: true,
+			Path:    filepath.Join(t.TempDir(), "snapshots.db"),
+		},
+	}
+	c := container.NewContainer(logger.NewNopLogger(), config)
+	store, err := snapshot.NewStore(c)
+	if err != nil {
+		t.Fatalf("NewStore: %v", err)
+	}
+	defer store.Close()
+
+	startedAt := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	runs := []struct {
+		codes       []string
+		finish      bool
+		interrupted bool
+	}{
+		{codes: []string{"8617001", "8617002"}, finish: true},
+		{codes: []string{"8617001"}, finish: true, interrupted: true},
+		{codes: []string{"8617001"}},
+	}
+	var completeRunID string
+	for i, r := range runs {
+		run, err := store.StartRun(startedAt.Add(time.Duration(i) * time.Hour))
+		if err != nil {
+			t.Fatalf("StartRun: %v", err)
+		}
+		if i == 0 {
+			completeRunID = run.ID
+		}
+		for index, code := range r.codes {
+			if err := store.Put(snapshot.Snapshot{RunID: run.ID, Code: code, Index: index}); err != nil {
+				t.Fatalf("Put: %v", err)
+			}
+		}
+		if r.finish {
+			if err := store.FinishRun(run.ID, startedAt.Add(time.Duration(i)*time.Hour+time.Minute), r.interrupted); err != nil {
+				t.Fatalf("FinishRun: %v", err)
+			}
+		}
+	}
+
+	snapshots, err := NewHistoryWorker(c, store).LastRunSnapshots()
+	if err != nil {
+		t.Fatalf("LastRunSnapshots: %v", err)
+	}
+	if len(snapshots) != 2 || snapshots[0].RunID != completeRunID {
+		t.Errorf("LastRunSnapshots = %+v, want the 2 snapshots of run %s", snapshots, completeRunID)
+	}
+}
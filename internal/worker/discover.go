@@ -0,0 +1,65 @@
+package worker
+
+import (
+	"context"
+	"dniprom-cli/internal/client"
+	"dniprom-cli/internal/container"
+	"dniprom-cli/internal/model/app"
+	"dniprom-cli/pkg/logger"
+)
+
+type Discover struct {
+	container    container.Container
+	dniproClient client.DniproClient
+}
+
+func NewDiscoverWorker(container container.Container, dniproClient client.DniproClient) *Discover {
+	return &Discover{
+		container:    container,
+		dniproClient: dniproClient,
+	}
+}
+
+// FilterByCategory reads the category path of every product from the
+// breadcrumbs of its product page and keeps the products matching filter.
+// Products whose page can't be read are dropped, since their category is
+// unknown.
+func (d *Discover) FilterByCategory(
+	ctx context.Context,
+	products []app.DiscoveredProduct,
+	filter app.CategoryFilter,
+) ([]app.DiscoveredProduct, error) {
+	log := d.container.GetLogger()
+	if filter.IsEmpty() {
+		return products, nil
+	}
+	filtered := make([]app.DiscoveredProduct, 0, len(products))
+	unreadable := 0
+	for _, product := range products {
+		page, err := d.dniproClient.FetchProductPage(ctx, product.URL)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return filtered, ctxErr
+		}
+		if err != nil {
+			unreadable++
+			log.Warn(
+				"fail to read product category, product skipped",
+				logger.F("code", product.Code),
+				logger.F("url", product.URL),
+				logger.FError(err),
+			)
+			continue
+		}
+		product.CategoryPath = page.CategoryPath
+		if filter.Matches(product.CategoryPath) {
+			filtered = append(filtered, product)
+		}
+	}
+	log.Info(
+		"products filtered by category",
+		logger.F("count", len(products)),
+		logger.F("kept", len(filtered)),
+		logger.F("unreadable", unreadable),
+	)
+	return filtered, nil
+}
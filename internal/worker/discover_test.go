@@ -0,0 +1,62 @@
+package worker
+
+import (
+	"context"
+	"dniprom-cli/internal/model/app"
+	"slices"
+	"testing"
+)
+
+func TestDiscoverFilterByCategory(t *testing.T) {
+	tests := []struct {
+		name      string
+		filter    app.CategoryFilter
+		wantCodes []string
+	}{
+		{
+			name:      "no filter",
+			wantCodes: []string{"8617001", "2000000", "6000000"},
+		},
+		{
+			name:      "include",
+			filter:    app.CategoryFilter{Include: []string{"дрилі"}},
+			wantCodes: []string{"8617001"},
+		},
+		{
+			// 2000000 has no readable product page, so its category is unknown.
+			name:      "exclude",
+			filter:    app.CategoryFilter{Exclude: []string{"ДРИЛІ"}},
+			wantCodes: []string{"6000000"},
+		},
+		{
+			name: "include and exclude",
+			filter: app.CategoryFilter{
+				Include: []string{"електроінструмент"},
+				Exclude: []string{"шуруповерти"},
+			},
+			wantCodes: []string{},
+		},
+	}
+
+	w := newReplayWarranty(t)
+	discoverWorker := NewDiscoverWorker(w.container, w.dniproClient)
+	products, err := discoverWorker.DiscoverBySitemap(context.Background(), "sitemap.xml")
+	if err != nil {
+		t.Fatalf("DiscoverBySitemap: %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered, err := discoverWorker.FilterByCategory(context.Background(), products, tt.filter)
+			if err != nil {
+				t.Fatalf("FilterByCategory: %v", err)
+			}
+			codes := make([]string, 0, len(filtered))
+			for _, product := range filtered {
+				codes = append(codes, product.Code)
+			}
+			if !slices.Equal(codes, tt.wantCodes) {
+				t.Errorf("codes = %q, want %q", codes, tt.wantCodes)
+			}
+		})
+	}
+}
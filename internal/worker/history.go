@@ -0,0 +1,125 @@
+package worker
+
+import (
+	"dniprom-cli/internal/container"
+	"dniprom-cli/internal/model/app"
+	"dniprom-cli/internal/service/snapshot"
+	"dniprom-cli/pkg/logger"
+	"time"
+)
+
+type History struct {
+	container container.Container
+	store     snapshot.Store
+}
+
+func NewHistoryWorker(container container.Container, store snapshot.Store) *History {
+	return &History{
+		container: container,
+		store:     store,
+	}
+}
+
+// Timeline returns the state of a product in every run that recorded it,
+// oldest first.
+func (h *History) Timeline(code string) ([]app.PriceHistoryPoint, error) {
+	log := h.container.GetLogger()
+	snapshots, err := h.store.History(code)
+	if err != nil {
+		log.Error("fail to read product history", logger.F("productCode", code), logger.FError(err))
+		return nil, err
+	}
+	points := make([]app.PriceHistoryPoint, 0, len(snapshots))
+	for _, item := range snapshots {
+		productWarranty := item.Product.ProductWarranty(item.Code)
+		points = append(points, app.PriceHistoryPoint{
+			RunID:            item.RunID,
+			RecordedAt:       item.RecordedAt,
+			Title:            productWarranty.Title,
+			OldPrice:         productWarranty.OldPriceAmount,
+			NewPrice:         productWarranty.NewPriceAmount,
+			WarrantyText:     productWarranty.WarrantyText,
+			WarrantyDuration: productWarranty.WarrantyDuration,
+			Status:           productWarranty.Status,
+		})
+	}
+	return points, nil
+}
+
+// PriceChangesSince lists the products whose latest prices differ from the
+// prices they had at since. Products first seen after since are compared with
+// their first snapshot. Snapshots of failed fetches carry no prices and are
+// ignored.
+func (h *History) PriceChangesSince(since time.Time) ([]app.PriceChange, error) {
+	log := h.container.GetLogger()
+	runs, err := h.store.Runs()
+	if err != nil {
+		log.Error("fail to read snapshot runs", logger.FError(err))
+		return nil, err
+	}
+
+	type productPrices struct {
+		baseline  *snapshot.Snapshot
+		latest    *snapshot.Snapshot
+		changedAt time.Time
+	}
+	var codes []string
+	prices := make(map[string]*productPrices)
+	for _, run := range runs {
+		snapshots, err := h.store.RunSnapshots(run.ID)
+		if err != nil {
+			log.Error("fail to read run snapshots", logger.F("runID", run.ID), logger.FError(err))
+			return nil, err
+		}
+		for i := range snapshots {
+			item := &snapshots[i]
+			if item.Product.Status != app.ProductStatusOK {
+				continue
+			}
+			product, ok := prices[item.Code]
+			if !ok {
+				product = &productPrices{}
+				prices[item.Code] = product
+				codes = append(codes, item.Code)
+			}
+			if product.latest != nil && !samePrices(product.latest.Product, item.Product) {
+				product.changedAt = item.RecordedAt
+			}
+			if item.RecordedAt.Before(since) || product.baseline == nil {
+				product.baseline = item
+			}
+			product.latest = item
+		}
+	}
+
+	var changes []app.PriceChange
+	for _, code := range codes {
+		product := prices[code]
+		was, now := product.baseline.Product, product.latest.Product
+		if samePrices(was, now) {
+			continue
+		}
+		changes = append(changes, app.PriceChange{
+			Code:        code,
+			Title:       now.Title,
+			Since:       product.baseline.RecordedAt,
+			ChangedAt:   product.changedAt,
+			OldPriceWas: was.OldPriceAmount,
+			OldPrice:    now.OldPriceAmount,
+			NewPriceWas: was.NewPriceAmount,
+			NewPrice:    now.NewPriceAmount,
+		})
+	}
+	return changes, nil
+}
+
+func samePrices(a, b snapshot.Product) bool {
+	return samePrice(a.OldPriceAmount, b.OldPriceAmount) && samePrice(a.NewPriceAmount, b.NewPriceAmount)
+}
+
+func samePrice(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
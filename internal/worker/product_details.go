@@ -0,0 +1,123 @@
+package worker
+
+import (
+	"context"
+	"dniprom-cli/internal/container"
+	"dniprom-cli/internal/model/app"
+	"dniprom-cli/pkg/logger"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+func parseEnrichmentFields(container container.Container) []app.EnrichmentField {
+	log := container.GetLogger()
+	values := container.GetConfig().Enrichment
+	fields := make([]app.EnrichmentField, 0, len(values))
+	for _, value := range values {
+		field, err := app.ParseEnrichmentField(strings.ToLower(strings.TrimSpace(value)))
+		if err != nil {
+			log.Warn("skip enrichment field", logger.FError(err))
+			continue
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// getProductDetails collects the requested enrichment fields. The product
+// page URL comes from the sitemap and the other details from the schema.org
+// data of that page. Details that can't be fetched are left empty, and so are
+// all of them when the page turns out to describe another product.
+func (w *Warranty) getProductDetails(ctx context.Context, code string) app.ProductDetails {
+	log := w.container.GetLogger()
+	config := w.container.GetConfig()
+	var details app.ProductDetails
+	if len(w.enrichmentFields) == 0 {
+		return details
+	}
+
+	pageURL := w.getProductURL(ctx, code)
+	if pageURL == "" {
+		log.Debug("product page not found in sitemap", logger.F("code", code))
+		return details
+	}
+	if slices.Contains(w.enrichmentFields, app.EnrichmentURL) {
+		details.URL = pageURL
+	}
+	if !slices.ContainsFunc(w.enrichmentFields, func(field app.EnrichmentField) bool {
+		return field != app.EnrichmentURL
+	}) {
+		return details
+	}
+
+	page, err := w.dniproClient.FetchProductPage(ctx, pageURL)
+	if err != nil {
+		log.Warn(
+			"fail to fetch product page",
+			logger.F("code", code),
+			logger.F("url", pageURL),
+			logger.FError(err),
+		)
+		return details
+	}
+	if page.SKU != "" && page.SKU != code {
+		log.Warn(
+			"product page from sitemap belongs to another product",
+			logger.F("code", code),
+			logger.F("url", pageURL),
+			logger.F("sku", page.SKU),
+		)
+		return app.ProductDetails{}
+	}
+	for _, field := range w.enrichmentFields {
+		switch field {
+		case app.EnrichmentCategory:
+			details.CategoryPath = page.CategoryPath
+		case app.EnrichmentBrand:
+			details.Brand = page.Brand
+		case app.EnrichmentAvailability:
+			details.Availability = page.Availability
+		case app.EnrichmentImage:
+			details.ImageURL = resolveURL(config.BaseURL, page.Image)
+		case app.EnrichmentRating:
+			details.Rating = page.Rating
+		}
+	}
+	return details
+}
+
+// getProductURL looks the product page up in the sitemap. The sitemap is
+// walked once per run, on the first lookup.
+func (w *Warranty) getProductURL(ctx context.Context, code string) string {
+	w.productURLsOnce.Do(func() {
+		log := w.container.GetLogger()
+		discoverWorker := NewDiscoverWorker(w.container, w.dniproClient)
+		products, err := discoverWorker.DiscoverBySitemap(ctx, w.container.GetConfig().Discover.SitemapURL)
+		if err != nil {
+			log.Warn("fail to load product pages from sitemap", logger.FError(err))
+		}
+		w.productURLs = make(map[string]string, len(products))
+		for _, product := range products {
+			w.productURLs[product.Code] = product.URL
+		}
+	})
+	return w.productURLs[code]
+}
+
+// resolveURL turns the site-relative links returned by Dnipro-M into
+// absolute ones.
+func resolveURL(baseURL string, ref string) string {
+	if ref == "" {
+		return ""
+	}
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return ref
+	}
+	resolved, err := base.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return resolved.String()
+}
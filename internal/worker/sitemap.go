@@ -0,0 +1,107 @@
+package worker
+
+import (
+	"context"
+	"dniprom-cli/internal/model/app"
+	"dniprom-cli/pkg/logger"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DiscoverBySitemap walks the configured sitemap index and its product
+// sitemaps and returns the products whose URL carries a product code.
+func (d *Discover) DiscoverBySitemap(ctx context.Context, sitemapURL string) ([]app.DiscoveredProduct, error) {
+	log := d.container.GetLogger()
+	config := d.container.GetConfig().Discover
+	codeRegexp, err := regexp.Compile(config.CodeRegexp)
+	if err != nil {
+		return nil, fmt.Errorf("invalid discover.code_regexp: %w", err)
+	}
+	if codeRegexp.NumSubexp() < 1 {
+		return nil, fmt.Errorf("discover.code_regexp %q has no capture group", config.CodeRegexp)
+	}
+
+	queue := []string{sitemapURL}
+	visited := make(map[string]bool)
+	seenCodes := make(map[string]bool)
+	var products []app.DiscoveredProduct
+	for len(queue) > 0 {
+		location := queue[0]
+		queue = queue[1:]
+		if visited[location] {
+			continue
+		}
+		visited[location] = true
+
+		sitemap, err := d.dniproClient.FetchSitemap(ctx, location)
+		if err != nil {
+			log.Error("fail to fetch sitemap", logger.F("sitemap", location), logger.FError(err))
+			return products, err
+		}
+		if sitemap.IsIndex() {
+			for _, child := range sitemap.Sitemaps {
+				if config.ProductSitemaps != "" && !strings.Contains(child.Loc, config.ProductSitemaps) {
+					continue
+				}
+				queue = append(queue, strings.TrimSpace(child.Loc))
+			}
+			continue
+		}
+
+		skipped := 0
+		for _, productURL := range sitemap.URLs {
+			loc := strings.TrimSpace(productURL.Loc)
+			match := codeRegexp.FindStringSubmatch(loc)
+			if match == nil || match[1] == "" {
+				skipped++
+				continue
+			}
+			if seenCodes[match[1]] {
+				continue
+			}
+			seenCodes[match[1]] = true
+			products = append(products, app.DiscoveredProduct{
+				Code: match[1],
+				URL:  loc,
+			})
+		}
+		log.Debug(
+			"sitemap discovered",
+			logger.F("sitemap", location),
+			logger.F("urls", len(sitemap.URLs)),
+			logger.F("skipped", skipped),
+			logger.F("products", len(products)),
+		)
+	}
+	return products, nil
+}
+
+// DiffCodes returns the codes present only in discovered (newly listed) and
+// only in current (delisted), both sorted.
+func DiffCodes(current []string, discovered []string) ([]string, []string) {
+	currentSet := make(map[string]bool, len(current))
+	for _, code := range current {
+		currentSet[strings.TrimSpace(code)] = true
+	}
+	discoveredSet := make(map[string]bool, len(discovered))
+	for _, code := range discovered {
+		discoveredSet[strings.TrimSpace(code)] = true
+	}
+
+	var added, removed []string
+	for code := range discoveredSet {
+		if !currentSet[code] {
+			added = append(added, code)
+		}
+	}
+	for code := range currentSet {
+		if !discoveredSet[code] {
+			removed = append(removed, code)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
@@ -1,6 +1,7 @@
 package worker
 
 import (
+	"context"
 	"dniprom-cli/internal/client"
 	"dniprom-cli/internal/container"
 	"dniprom-cli/internal/model/app"
@@ -8,21 +9,27 @@ import (
 	"dniprom-cli/pkg/logger"
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
 )
 
 type Warranty struct {
-	container    container.Container
-	dniproClient client.DniproClient
+	container        container.Container
+	dniproClient     client.DniproClient
+	enrichmentFields []app.EnrichmentField
+	productURLs      map[string]string
+	productURLsOnce  sync.Once
 }
 
 func NewWarrantyWorker(container container.Container, dniproClient client.DniproClient) *Warranty {
 	return &Warranty{
-		container:    container,
-		dniproClient: dniproClient,
+		container:        container,
+		dniproClient:     dniproClient,
+		enrichmentFields: parseEnrichmentFields(container),
 	}
 }
 
-func (w *Warranty) FetchByCode(code string) (*app.ProductWarranty, error) {
+func (w *Warranty) FetchByCode(ctx context.Context, code string) (*app.ProductWarranty, error) {
 	log := w.container.GetLogger()
 	const defaultMissingValue = "unknown"
 	productWarranty := app.ProductWarranty{
@@ -32,60 +39,165 @@ func (w *Warranty) FetchByCode(code string) (*app.ProductWarranty, error) {
 		WarrantyText: defaultMissingValue,
 		OldPrice:     defaultMissingValue,
 		NewPrice:     defaultMissingValue,
+		Status:       app.ProductStatusError,
 	}
 
-	productResponse, err := w.dniproClient.FetchAutocompleteProduct(code)
+	productResponse, err := w.dniproClient.FetchAutocompleteProduct(ctx, code)
 	if err != nil {
 		log.Error("fail to fetch autocomplete product", logger.FError(err))
+		productWarranty.Status = GetProductStatus(err)
 		return &productWarranty, err
 	}
-
-	if productResponse == nil {
-		return &productWarranty, errors.New("product not found")
-	}
-	productWarranty.Title = GetProductName(productResponse)
+	productWarranty.Status = app.ProductStatusOK
+	productWarranty.Title = GetProductName(productResponse, w.container.GetConfig().Title.Languages)
+	productWarranty.Names = getLocalizedNames(productResponse.Name)
+	productWarranty.Details = w.getProductDetails(ctx, code)
 	log.Debug(
 		"success to fetch autocomplete product",
 		logger.F("code", code),
 	)
 
 	productWarranty.ID = productResponse.ID
-	warrantyText, err := w.dniproClient.GetWarranty(productResponse.ID)
+	warrantyEntries, err := w.dniproClient.GetWarranty(ctx, productResponse.ID)
+	if errors.Is(err, client.ErrNotFound) {
+		log.Debug(
+			"product has no service maintenance info",
+			logger.F("code", code),
+		)
+		err = nil
+	}
 	if err != nil {
 		log.Error(
 			"fail to fetch warranty's product",
 			logger.F("code", code),
 			logger.FError(err),
 		)
+		productWarranty.Status = GetProductStatus(err)
+		if ctx.Err() != nil {
+			return &productWarranty, err
+		}
+	}
+	// A failed warranty lookup still fills in the product and its prices, the
+	// error is returned along with them.
+	warrantyErr := err
+	productWarranty.Warranties = GetWarrantyEntries(warrantyEntries)
+	productWarranty.WarrantyText = FormatWarrantyText(productWarranty.Warranties)
+	productWarranty.ServiceNotes = FormatServiceNotes(productWarranty.Warranties)
+	for _, warranty := range productWarranty.Warranties {
+		if warranty.Duration.Parsed {
+			productWarranty.WarrantyDuration = warranty.Duration
+			break
+		}
 	}
-	if warrantyText == "" {
-		warrantyText = defaultMissingValue
+	if productWarranty.WarrantyText == "" {
+		productWarranty.WarrantyText = defaultMissingValue
 	}
-	productWarranty.WarrantyText = warrantyText
 	if oldPrice := productResponse.PriceOld.Value; oldPrice != nil {
 		productWarranty.OldPrice = getFormattedPrice(*oldPrice)
+		productWarranty.OldPriceAmount = oldPrice
 	}
 	if newPrice := productResponse.PriceNew.Value; newPrice != nil {
 		productWarranty.NewPrice = getFormattedPrice(*newPrice)
+		productWarranty.NewPriceAmount = newPrice
 	}
-	return &productWarranty, nil
+	return &productWarranty, warrantyErr
 }
 
-func GetProductName(product *network.Product) string {
+// GetProductName returns the first non-empty name following the languages
+// preference order.
+func GetProductName(product *network.Product, languages []string) string {
 	const defaultProductTitle = "unknown"
 	if product == nil {
 		return defaultProductTitle
 	}
-	if product.Name.UK != "" {
-		return product.Name.UK
-	} else if product.Name.RU != "" {
-		return product.Name.RU
-	} else if product.Name.EN != "" {
-		return product.Name.EN
+	if name := getLocalizedNames(product.Name).Preferred(languages); name != "" {
+		return name
 	}
 	return defaultProductTitle
 }
 
+func getLocalizedNames(name network.LocalizedName) app.LocalizedNames {
+	return app.LocalizedNames{
+		UK: name.UK,
+		RU: name.RU,
+		EN: name.EN,
+	}
+}
+
+func GetProductStatus(err error) app.ProductStatus {
+	switch {
+	case err == nil:
+		return app.ProductStatusOK
+	case errors.Is(err, client.ErrProductNotFound), errors.Is(err, client.ErrNotFound):
+		return app.ProductStatusNotFound
+	case errors.Is(err, client.ErrNoExactMatch):
+		return app.ProductStatusNoExactMatch
+	case errors.Is(err, client.ErrAmbiguousMatch):
+		return app.ProductStatusAmbiguous
+	case errors.Is(err, network.ErrSchemaMismatch):
+		return app.ProductStatusSchema
+	case errors.Is(err, client.ErrRateLimited):
+		return app.ProductStatusRateLimited
+	case errors.Is(err, client.ErrUpstream):
+		return app.ProductStatusUpstream
+	case errors.Is(err, client.ErrBadContentType), errors.Is(err, client.ErrUnexpectedStatus):
+		return app.ProductStatusBadResponse
+	}
+	return app.ProductStatusError
+}
+
+func GetWarrantyEntries(entries []network.WarrantyEntry) []app.WarrantyEntry {
+	warranties := make([]app.WarrantyEntry, 0, len(entries))
+	for _, entry := range entries {
+		warranty := app.WarrantyEntry{
+			Component:    strings.TrimSpace(entry.Component),
+			Conditions:   strings.TrimSpace(entry.Conditions),
+			ServiceNotes: strings.TrimSpace(entry.Service),
+		}
+		if entry.Warranty != nil {
+			warranty.Term = strings.TrimSpace(*entry.Warranty)
+			warranty.Duration = ParseWarrantyDuration(warranty.Term)
+		}
+		warranties = append(warranties, warranty)
+	}
+	return warranties
+}
+
+// FormatWarrantyText renders warranty entries as a multi-line cell, one line
+// per entry, e.g. "Battery: 12 місяців (registration required)".
+func FormatWarrantyText(warranties []app.WarrantyEntry) string {
+	lines := make([]string, 0, len(warranties))
+	for _, warranty := range warranties {
+		if warranty.Term == "" {
+			continue
+		}
+		line := warranty.Term
+		if warranty.Component != "" {
+			line = warranty.Component + ": " + line
+		}
+		if warranty.Conditions != "" {
+			line += " (" + warranty.Conditions + ")"
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func FormatServiceNotes(warranties []app.WarrantyEntry) string {
+	lines := make([]string, 0, len(warranties))
+	for _, warranty := range warranties {
+		if warranty.ServiceNotes == "" {
+			continue
+		}
+		line := warranty.ServiceNotes
+		if warranty.Component != "" {
+			line = warranty.Component + ": " + line
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
 func getFormattedPrice(price float64) string {
 	return fmt.Sprintf("%.2f", price)
 }
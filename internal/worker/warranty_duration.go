@@ -0,0 +1,124 @@
+package worker
+
+import (
+	"dniprom-cli/internal/model/app"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	warrantyDurationRegexp    = regexp.MustCompile(`(\d+(?:[.,]\d+)?)\s*-?\s*(\p{L}+)\.?`)
+	warrantyAlternativeRegexp = regexp.MustCompile(`(?i)\s+(?:або|или|or)\s+`)
+	warrantyParenthesesRegexp = regexp.MustCompile(`\(([^()]*)\)`)
+)
+
+// warrantyUnits maps the lower-cased Ukrainian, Russian and English month and
+// year words to their length in months. Words are matched whole, so hours
+// ("годин", "часов") and units shorter than a month are never counted.
+var warrantyUnits = map[string]float64{
+	"міс":     1,
+	"місяць":  1,
+	"місяця":  1,
+	"місяці":  1,
+	"місяців": 1,
+	"мес":     1,
+	"месяц":   1,
+	"месяца":  1,
+	"месяцев": 1,
+	"mo":      1,
+	"mos":     1,
+	"month":   1,
+	"months":  1,
+	"р":       12,
+	"рік":     12,
+	"роки":    12,
+	"року":    12,
+	"років":   12,
+	"г":       12,
+	"год":     12,
+	"года":    12,
+	"лет":     12,
+	"yr":      12,
+	"yrs":     12,
+	"year":    12,
+	"years":   12,
+}
+
+// ParseWarrantyDuration extracts the base and extended warranty length in
+// months from free text such as "36 місяців" or "2 роки + 1 рік при
+// реєстрації". Everything after "+" counts as the extended term. Terms joined
+// by "or" or given in parentheses are alternatives: only the first one with a
+// duration is counted and the others are kept, along with whatever text is
+// left once durations are removed, as the conditions.
+func ParseWarrantyDuration(text string) app.WarrantyDuration {
+	var duration app.WarrantyDuration
+	parts := strings.Split(text, "+")
+	conditions := make([]string, 0, len(parts))
+	for i, part := range parts {
+		months, partConditions, ok := parseWarrantyPart(part)
+		if ok {
+			duration.Parsed = true
+			if i == 0 {
+				duration.BaseMonths += months
+			} else {
+				duration.ExtendedMonths += months
+			}
+		}
+		conditions = append(conditions, partConditions...)
+	}
+	if duration.Parsed {
+		duration.Conditions = strings.Join(conditions, "; ")
+	}
+	return duration
+}
+
+func parseWarrantyPart(text string) (int, []string, bool) {
+	var alternatives []string
+	text = warrantyParenthesesRegexp.ReplaceAllStringFunc(text, func(match string) string {
+		alternatives = append(alternatives, warrantyParenthesesRegexp.FindStringSubmatch(match)[1])
+		return " "
+	})
+	alternatives = append(warrantyAlternativeRegexp.Split(text, -1), alternatives...)
+
+	months, found := 0, false
+	conditions := make([]string, 0, len(alternatives))
+	for _, alternative := range alternatives {
+		if !found {
+			alternativeMonths, rest, ok := parseWarrantyMonths(alternative)
+			if ok {
+				months, found = alternativeMonths, true
+				alternative = rest
+			}
+		}
+		if alternative = cleanWarrantyText(alternative); alternative != "" {
+			conditions = append(conditions, alternative)
+		}
+	}
+	return months, conditions, found
+}
+
+func parseWarrantyMonths(text string) (int, string, bool) {
+	var months float64
+	found := false
+	rest := warrantyDurationRegexp.ReplaceAllStringFunc(text, func(match string) string {
+		groups := warrantyDurationRegexp.FindStringSubmatch(match)
+		value, err := strconv.ParseFloat(strings.ReplaceAll(groups[1], ",", "."), 64)
+		if err != nil {
+			return match
+		}
+		unitMonths, ok := warrantyUnits[strings.ToLower(groups[2])]
+		if !ok {
+			return match
+		}
+		months += value * unitMonths
+		found = true
+		return ""
+	})
+	return int(math.Round(months)), rest, found
+}
+
+func cleanWarrantyText(text string) string {
+	return strings.Trim(strings.Join(strings.Fields(text), " "), " ,.;:()")
+}
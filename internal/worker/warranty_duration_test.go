@@ -0,0 +1,70 @@
+package worker
+
+import (
+	"dniprom-cli/internal/model/app"
+	"testing"
+)
+
+func TestParseWarrantyDuration(t *testing.T) {
+	tests := []struct {
+		text string
+		want app.WarrantyDuration
+	}{
+		// Ukrainian
+		{text: "36 місяців", want: app.WarrantyDuration{BaseMonths: 36, Parsed: true}},
+		{text: "12 міс.", want: app.WarrantyDuration{BaseMonths: 12, Parsed: true}},
+		{text: "1 рік 6 місяців", want: app.WarrantyDuration{BaseMonths: 18, Parsed: true}},
+		{text: "1,5 року", want: app.WarrantyDuration{BaseMonths: 18, Parsed: true}},
+		{
+			text: "2 роки + 1 рік при реєстрації",
+			want: app.WarrantyDuration{BaseMonths: 24, ExtendedMonths: 12, Conditions: "при реєстрації", Parsed: true},
+		},
+		{
+			text: "24 місяці або 500 годин роботи",
+			want: app.WarrantyDuration{BaseMonths: 24, Conditions: "500 годин роботи", Parsed: true},
+		},
+		{
+			text: "500 годин роботи або 24 місяці",
+			want: app.WarrantyDuration{BaseMonths: 24, Conditions: "500 годин роботи", Parsed: true},
+		},
+		{
+			text: "2 роки (3 роки при реєстрації)",
+			want: app.WarrantyDuration{BaseMonths: 24, Conditions: "3 роки при реєстрації", Parsed: true},
+		},
+		{text: "14 днів"},
+		{text: "500 годин"},
+		{text: "без гарантії"},
+		{text: ""},
+		// Russian
+		{text: "3 года", want: app.WarrantyDuration{BaseMonths: 36, Parsed: true}},
+		{text: "1 год", want: app.WarrantyDuration{BaseMonths: 12, Parsed: true}},
+		{text: "5 лет", want: app.WarrantyDuration{BaseMonths: 60, Parsed: true}},
+		{
+			text: "24 месяца или 300 часов",
+			want: app.WarrantyDuration{BaseMonths: 24, Conditions: "300 часов", Parsed: true},
+		},
+		{text: "2 недели"},
+		// English
+		{
+			text: "12-month warranty",
+			want: app.WarrantyDuration{BaseMonths: 12, Conditions: "warranty", Parsed: true},
+		},
+		{text: "2 years", want: app.WarrantyDuration{BaseMonths: 24, Parsed: true}},
+		{
+			text: "1 year + 6 months with registration",
+			want: app.WarrantyDuration{BaseMonths: 12, ExtendedMonths: 6, Conditions: "with registration", Parsed: true},
+		},
+		{
+			text: "24 months or 500 hours",
+			want: app.WarrantyDuration{BaseMonths: 24, Conditions: "500 hours", Parsed: true},
+		},
+		{text: "30 days"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := ParseWarrantyDuration(tt.text); got != tt.want {
+				t.Errorf("ParseWarrantyDuration(%q) = %+v, want %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
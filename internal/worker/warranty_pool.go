@@ -0,0 +1,105 @@
+package worker
+
+import (
+	"context"
+	"dniprom-cli/internal/container"
+	"dniprom-cli/internal/model/app"
+	"sync"
+)
+
+type WarrantyResult struct {
+	Index           int
+	Code            string
+	ProductWarranty *app.ProductWarranty
+	Err             error
+}
+
+type WarrantyPool struct {
+	container container.Container
+	warranty  *Warranty
+	workers   int
+}
+
+func NewWarrantyPool(container container.Container, warranty *Warranty) *WarrantyPool {
+	workers := container.GetConfig().Concurrency.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	return &WarrantyPool{
+		container: container,
+		warranty:  warranty,
+		workers:   workers,
+	}
+}
+
+// FetchByCodes fetches every code concurrently and emits the results in the
+// same order as codes, regardless of the order in which they complete. Once
+// ctx is done no new codes are started and the channel is closed after the
+// in-flight ones are delivered or abandoned.
+func (p *WarrantyPool) FetchByCodes(ctx context.Context, codes []string) <-chan WarrantyResult {
+	jobs := make(chan int)
+	completed := make(chan WarrantyResult, p.workers)
+	ordered := make(chan WarrantyResult)
+
+	var wg sync.WaitGroup
+	for i := 0; i < p.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				productWarranty, err := p.warranty.FetchByCode(ctx, codes[index])
+				result := WarrantyResult{
+					Index:           index,
+					Code:            codes[index],
+					ProductWarranty: productWarranty,
+					Err:             err,
+				}
+				select {
+				case completed <- result:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for index := range codes {
+			select {
+			case jobs <- index:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(completed)
+	}()
+
+	go func() {
+		defer close(ordered)
+		pending := make(map[int]WarrantyResult)
+		next := 0
+		for result := range completed {
+			pending[result.Index] = result
+			for {
+				result, ok := pending[next]
+				if !ok {
+					break
+				}
+				delete(pending, next)
+				select {
+				case ordered <- result:
+				case <-ctx.Done():
+					return
+				}
+				next++
+			}
+		}
+	}()
+
+	return ordered
+}
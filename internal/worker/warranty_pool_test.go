@@ -0,0 +1,120 @@
+package worker
+
+import (
+	"context"
+	"dniprom-cli/internal/client"
+	"dniprom-cli/internal/container"
+	"dniprom-cli/internal/fakeserver"
+	"dniprom-cli/internal/model"
+	"dniprom-cli/pkg/logger"
+	"testing"
+	"time"
+)
+
+// newFakeServerPool returns a warranty pool whose client talks to a fake
+// Dnipro-M server serving catalog.
+func newFakeServerPool(t *testing.T, catalog *fakeserver.Catalog, workers int) *WarrantyPool {
+	t.Helper()
+	server := fakeserver.NewServer(catalog)
+	t.Cleanup(server.Close)
+	config := &model.Config{
+		BaseURL: server.URL + "/",
+		Concurrency: model.ConcurrencyConfig{
+			Workers:           workers,
+			RequestsPerSecond: 100,
+			Burst:             10,
+		},
+		Retry: model.RetryConfig{
+			MaxAttempts: 3,
+			BaseBackoff: 10 * time.Millisecond,
+			MaxBackoff:  2 * time.Second,
+		},
+		HTTP:  model.HTTPConfig{Timeout: 5 * time.Second},
+		Title: model.TitleConfig{Languages: []string{"uk"}},
+	}
+	c := container.NewContainer(logger.NewNopLogger(), config)
+	dniproClient, err := client.NewDniproClient(c)
+	if err != nil {
+		t.Fatalf("NewDniproClient: %v", err)
+	}
+	return NewWarrantyPool(c, NewWarrantyWorker(c, dniproClient))
+}
+
+func fakeProduct(id int64, code string, latency time.Duration) fakeserver.Product {
+	price := 100.0
+	return fakeserver.Product{
+		ID:         id,
+		VendorCode: code,
+		NameUK:     "Product " + code,
+		PriceNew:   &price,
+		Warranty:   []fakeserver.Warranty{{Term: "12 місяців"}},
+		Latency:    latency,
+	}
+}
+
+// Results come back in input order even though the first products are the
+// slowest to answer.
+func TestWarrantyPoolKeepsInputOrder(t *testing.T) {
+	catalog := &fakeserver.Catalog{Products: []fakeserver.Product{
+		fakeProduct(1, "1000001", 60*time.Millisecond),
+		fakeProduct(2, "1000002", 40*time.Millisecond),
+		fakeProduct(3, "1000003", 20*time.Millisecond),
+		fakeProduct(4, "1000004", 0),
+	}}
+	codes := []string{"1000001", "1000002", "1000003", "1000004", "9999999"}
+	pool := newFakeServerPool(t, catalog, len(codes))
+
+	var got []string
+	for result := range pool.FetchByCodes(context.Background(), codes) {
+		if result.Index != len(got) {
+			t.Errorf("result %q has index %d, want %d", result.Code, result.Index, len(got))
+		}
+		got = append(got, result.Code)
+		if result.Code == "9999999" {
+			if result.Err == nil {
+				t.Errorf("FetchByCode(%q) error = nil, want not found", result.Code)
+			}
+			continue
+		}
+		if result.Err != nil {
+			t.Errorf("FetchByCode(%q) error = %v", result.Code, result.Err)
+		}
+	}
+	if len(got) != len(codes) {
+		t.Fatalf("got %d results, want %d", len(got), len(codes))
+	}
+	for i, code := range codes {
+		if got[i] != code {
+			t.Errorf("result %d = %q, want %q", i, got[i], code)
+		}
+	}
+}
+
+// The server allows one request per second, so the warranty request right
+// after the search is answered 429 and succeeds once Retry-After has passed.
+func TestWarrantyPoolRetriesRateLimited(t *testing.T) {
+	catalog := &fakeserver.Catalog{
+		RequestsPerSecond: 1,
+		RetryAfter:        1,
+		Products:          []fakeserver.Product{fakeProduct(1, "1000001", 0)},
+	}
+	pool := newFakeServerPool(t, catalog, 2)
+
+	startedAt := time.Now()
+	var results []WarrantyResult
+	for result := range pool.FetchByCodes(context.Background(), []string{"1000001"}) {
+		results = append(results, result)
+	}
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+	if err := results[0].Err; err != nil {
+		t.Fatalf("FetchByCode error = %v, want the retry to succeed", err)
+	}
+	if got := results[0].ProductWarranty.WarrantyText; got != "12 місяців" {
+		t.Errorf("WarrantyText = %q, want %q", got, "12 місяців")
+	}
+	if elapsed := time.Since(startedAt); elapsed < time.Second {
+		t.Errorf("run took %v, want at least the 1s Retry-After", elapsed)
+	}
+}
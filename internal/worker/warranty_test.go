@@ -0,0 +1,205 @@
+package worker
+
+import (
+	"context"
+	"dniprom-cli/internal/client"
+	"dniprom-cli/internal/container"
+	"dniprom-cli/internal/model"
+	"dniprom-cli/internal/model/app"
+	"dniprom-cli/internal/model/network"
+	"dniprom-cli/pkg/logger"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// newReplayWarranty returns a warranty worker whose client serves the
+// fixtures from testdata/fixtures without touching the network. The fixtures
+// are hand-written in the --record format, re-record them with
+// `warranty --record` against the live site when the payloads change.
+func newReplayWarranty(t *testing.T, enrichment ...string) *Warranty {
+	t.Helper()
+	config := &model.Config{
+		BaseURL: "https://dnipro-m.ua/",
+		Concurrency: model.ConcurrencyConfig{
+			Workers:           1,
+			RequestsPerSecond: 1,
+			Burst:             1,
+		},
+		Retry:    model.RetryConfig{MaxAttempts: 1},
+		Fixtures: model.FixturesConfig{ReplayDir: "testdata/fixtures"},
+		HTTP:     model.HTTPConfig{Timeout: time.Second},
+		Title:    model.TitleConfig{Languages: []string{"uk", "ru", "en"}},
+		Discover: model.DiscoverConfig{
+			SitemapURL: "sitemap.xml",
+			CodeRegexp: `(\d{6,})/?$`,
+		},
+		Enrichment: enrichment,
+	}
+	c := container.NewContainer(logger.NewNopLogger(), config)
+	dniproClient, err := client.NewDniproClient(c)
+	if err != nil {
+		t.Fatalf("NewDniproClient: %v", err)
+	}
+	return NewWarrantyWorker(c, dniproClient)
+}
+
+func TestWarrantyFetchByCode(t *testing.T) {
+	tests := []struct {
+		name         string
+		code         string
+		wantErr      error
+		wantStatus   app.ProductStatus
+		wantID       int64
+		wantTitle    string
+		wantWarranty string
+		wantNewPrice string
+		wantOldPrice string
+	}{
+		{
+			name:         "ok",
+			code:         "8617001",
+			wantStatus:   app.ProductStatusOK,
+			wantID:       101,
+			wantTitle:    "Дриль-шуруповерт CD-200BC",
+			wantWarranty: "36 місяців",
+			wantNewPrice: "2899.00",
+			wantOldPrice: "3299.00",
+		},
+		{
+			name:         "not found",
+			code:         "1000000",
+			wantErr:      client.ErrProductNotFound,
+			wantStatus:   app.ProductStatusNotFound,
+			wantID:       -1,
+			wantTitle:    "unknown",
+			wantWarranty: "unknown",
+			wantNewPrice: "unknown",
+			wantOldPrice: "unknown",
+		},
+		{
+			name:         "no exact match",
+			code:         "4000000",
+			wantErr:      client.ErrNoExactMatch,
+			wantStatus:   app.ProductStatusNoExactMatch,
+			wantID:       -1,
+			wantTitle:    "unknown",
+			wantWarranty: "unknown",
+			wantNewPrice: "unknown",
+			wantOldPrice: "unknown",
+		},
+		{
+			name:         "ambiguous",
+			code:         "2000000",
+			wantErr:      client.ErrAmbiguousMatch,
+			wantStatus:   app.ProductStatusAmbiguous,
+			wantID:       -1,
+			wantTitle:    "unknown",
+			wantWarranty: "unknown",
+			wantNewPrice: "unknown",
+			wantOldPrice: "unknown",
+		},
+		{
+			name:         "schema mismatch",
+			code:         "3000000",
+			wantErr:      network.ErrSchemaMismatch,
+			wantStatus:   app.ProductStatusSchema,
+			wantID:       -1,
+			wantTitle:    "unknown",
+			wantWarranty: "unknown",
+			wantNewPrice: "unknown",
+			wantOldPrice: "unknown",
+		},
+		{
+			name:         "warranty rate limited",
+			code:         "5000000",
+			wantErr:      client.ErrRateLimited,
+			wantStatus:   app.ProductStatusRateLimited,
+			wantID:       501,
+			wantTitle:    "Шліфмашина OS-30",
+			wantWarranty: "unknown",
+			wantNewPrice: "2199.00",
+			wantOldPrice: "unknown",
+		},
+	}
+
+	w := newReplayWarranty(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := w.FetchByCode(context.Background(), tt.code)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("FetchByCode(%q) error = %v", tt.code, err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("FetchByCode(%q) error = %v, want %v", tt.code, err, tt.wantErr)
+			}
+			if got == nil {
+				t.Fatalf("FetchByCode(%q) returned no product", tt.code)
+			}
+			if got.Status != tt.wantStatus {
+				t.Errorf("Status = %q, want %q", got.Status, tt.wantStatus)
+			}
+			if got.ID != tt.wantID {
+				t.Errorf("ID = %d, want %d", got.ID, tt.wantID)
+			}
+			if got.Title != tt.wantTitle {
+				t.Errorf("Title = %q, want %q", got.Title, tt.wantTitle)
+			}
+			if got.WarrantyText != tt.wantWarranty {
+				t.Errorf("WarrantyText = %q, want %q", got.WarrantyText, tt.wantWarranty)
+			}
+			if got.NewPrice != tt.wantNewPrice {
+				t.Errorf("NewPrice = %q, want %q", got.NewPrice, tt.wantNewPrice)
+			}
+			if got.OldPrice != tt.wantOldPrice {
+				t.Errorf("OldPrice = %q, want %q", got.OldPrice, tt.wantOldPrice)
+			}
+		})
+	}
+}
+
+func TestWarrantyFetchByCodeEnrichment(t *testing.T) {
+	w := newReplayWarranty(t, "category", "brand", "availability", "url", "image", "rating")
+	got, err := w.FetchByCode(context.Background(), "8617001")
+	if err != nil {
+		t.Fatalf("FetchByCode: %v", err)
+	}
+	details := got.Details
+	if want := "https://dnipro-m.ua/ua/p/drel-shurupovert-cd-200bc-8617001/"; details.URL != want {
+		t.Errorf("URL = %q, want %q", details.URL, want)
+	}
+	if want := "https://dnipro-m.ua/images/products/8617001.jpg"; details.ImageURL != want {
+		t.Errorf("ImageURL = %q, want %q", details.ImageURL, want)
+	}
+	if details.Brand != "Dnipro-M" {
+		t.Errorf("Brand = %q, want %q", details.Brand, "Dnipro-M")
+	}
+	if details.Availability != "InStock" {
+		t.Errorf("Availability = %q, want %q", details.Availability, "InStock")
+	}
+	if details.Rating == nil || *details.Rating != 4.8 {
+		t.Errorf("Rating = %v, want 4.8", details.Rating)
+	}
+	if want := []string{"Електроінструмент", "Дрилі-шуруповерти"}; !slices.Equal(details.CategoryPath, want) {
+		t.Errorf("CategoryPath = %q, want %q", details.CategoryPath, want)
+	}
+
+	// A product missing from the sitemap keeps its warranty, without details.
+	got, err = w.FetchByCode(context.Background(), "5000000")
+	if !errors.Is(err, client.ErrRateLimited) {
+		t.Fatalf("FetchByCode error = %v, want %v", err, client.ErrRateLimited)
+	}
+	if got.Details.URL != "" || got.Details.Brand != "" {
+		t.Errorf("Details = %+v, want empty", got.Details)
+	}
+
+	// A sitemap page whose SKU is another code is rejected with its URL.
+	got, err = w.FetchByCode(context.Background(), "6000000")
+	if err != nil {
+		t.Fatalf("FetchByCode: %v", err)
+	}
+	if got.Details.URL != "" || got.Details.Brand != "" {
+		t.Errorf("Details = %+v, want empty", got.Details)
+	}
+}
@@ -0,0 +1,43 @@
+package jsonx
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// FlexibleString accepts JSON strings, numbers and booleans, since the same
+// field may come as either depending on the endpoint.
+type FlexibleString string
+
+func (f *FlexibleString) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*f = ""
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var v string
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		*f = FlexibleString(v)
+		return nil
+	}
+
+	if string(data) == "true" || string(data) == "false" {
+		*f = FlexibleString(data)
+		return nil
+	}
+
+	var v json.Number
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&v); err != nil {
+		return err
+	}
+	*f = FlexibleString(v.String())
+	return nil
+}
+
+func (f FlexibleString) String() string {
+	return string(f)
+}
@@ -20,3 +20,10 @@ func (n *NullableFloat64) UnmarshalJSON(data []byte) error {
 	n.Value = &v
 	return nil
 }
+
+func (n NullableFloat64) MarshalJSON() ([]byte, error) {
+	if n.Value == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(*n.Value)
+}
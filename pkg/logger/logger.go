@@ -147,8 +147,17 @@ func NewLogger(env ENV) Logger {
 	}
 }
 
+// NewNopLogger returns a logger that discards everything, e.g. for tests.
+func NewNopLogger() Logger {
+	return logger{
+		lg: zap.NewNop(),
+	}
+}
+
 func buildLogger(env ENV, level zapcore.Level) *zap.Logger {
-	stdout := zapcore.AddSync(os.Stdout)
+	// Console logs go to stderr so that command output on stdout stays
+	// machine readable.
+	console := zapcore.AddSync(os.Stderr)
 	file := zapcore.AddSync(&lumberjack.Logger{
 		Filename:   "logs/app.log",
 		MaxSize:    10,
@@ -165,7 +174,7 @@ func buildLogger(env ENV, level zapcore.Level) *zap.Logger {
 		consoleEncoder := zapcore.NewConsoleEncoder(productionCfg)
 		fileEncoder := zapcore.NewJSONEncoder(productionCfg)
 		core := zapcore.NewTee(
-			zapcore.NewCore(consoleEncoder, stdout, level),
+			zapcore.NewCore(consoleEncoder, console, level),
 			zapcore.NewCore(fileEncoder, file, level),
 		)
 		return zap.New(core)
@@ -178,7 +187,7 @@ func buildLogger(env ENV, level zapcore.Level) *zap.Logger {
 		fileEncoder := zapcore.NewConsoleEncoder(developmentCfg)
 
 		core := zapcore.NewTee(
-			zapcore.NewCore(consoleEncoder, stdout, level),
+			zapcore.NewCore(consoleEncoder, console, level),
 			zapcore.NewCore(fileEncoder, file, level),
 		)
 		return zap.New(core)